@@ -1,6 +1,10 @@
 package app
 
-import "github.com/charmbracelet/bubbletea"
+import (
+	"github.com/charmbracelet/bubbletea"
+	"github.com/elisa-content-delivery/hit/internal/ui/auth"
+	"github.com/elisa-content-delivery/hit/internal/ui/reflog"
+)
 
 type SwitchViewMsg struct {
 	View View
@@ -18,6 +22,18 @@ func HandleGlobalKeys(msg tea.KeyMsg) (tea.Cmd, bool) {
 		return func() tea.Msg {
 			return SwitchViewMsg{View: -2}
 		}, true
+	case "u":
+		return func() tea.Msg {
+			return reflog.UndoMsg{}
+		}, true
+	case "L":
+		return func() tea.Msg {
+			return reflog.FocusMsg{}
+		}, true
+	case "A":
+		return func() tea.Msg {
+			return auth.OpenMsg{}
+		}, true
 	}
 	return nil, false
 }
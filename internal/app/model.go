@@ -1,7 +1,10 @@
 package app
 
 import (
+	"fmt"
+	"os"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -11,10 +14,21 @@ import (
 	"github.com/elisa-content-delivery/hit/internal/ui/auth"
 	"github.com/elisa-content-delivery/hit/internal/ui/branches"
 	"github.com/elisa-content-delivery/hit/internal/ui/ci"
+	"github.com/elisa-content-delivery/hit/internal/ui/commit"
+	"github.com/elisa-content-delivery/hit/internal/ui/commits"
+	"github.com/elisa-content-delivery/hit/internal/ui/compare"
+	"github.com/elisa-content-delivery/hit/internal/ui/conflict"
+	"github.com/elisa-content-delivery/hit/internal/ui/diagnostics"
 	"github.com/elisa-content-delivery/hit/internal/ui/org"
 	"github.com/elisa-content-delivery/hit/internal/ui/pr"
+	"github.com/elisa-content-delivery/hit/internal/ui/rebase"
 	"github.com/elisa-content-delivery/hit/internal/ui/reflog"
+	"github.com/elisa-content-delivery/hit/internal/ui/releases"
 	"github.com/elisa-content-delivery/hit/internal/ui/review"
+	"github.com/elisa-content-delivery/hit/internal/ui/stash"
+	"github.com/elisa-content-delivery/hit/internal/ui/status"
+	"github.com/elisa-content-delivery/hit/internal/ui/submodules"
+	"github.com/elisa-content-delivery/hit/internal/ui/worktrees"
 )
 
 type View int
@@ -22,53 +36,127 @@ type View int
 const (
 	ViewAuth View = iota
 	ViewBranches
+	ViewStatus
+	ViewStash
+	ViewWorktrees
 	ViewCI
+	ViewReleases
 	ViewPR
 	ViewReview
 	ViewOrg
+
+	// Views below are not tabs; they are opened from another view and
+	// highlight their parent tab.
+	ViewConflicts
+	ViewCommit
+	ViewLog
+	ViewRebase
+	ViewCompare
+	ViewSubmodules
+	// ViewDiagnostics opens on its own after sign-in when the token lacks
+	// scopes, or from the account screen, and highlights the tab it was
+	// opened from.
+	ViewDiagnostics
 )
 
-const reflogPaneWidth = 100
+func (v View) tab() View {
+	switch v {
+	case ViewConflicts, ViewLog, ViewRebase, ViewCompare, ViewSubmodules:
+		return ViewBranches
+	case ViewCommit:
+		return ViewStatus
+	}
+	return v
+}
+
+const (
+	reflogPaneWidth = 100
+	reflogMinWidth  = 200
+)
 
 var tabNames = []string{
 	styles.IconBranch + " Branches",
+	styles.IconEdit + " Status",
+	styles.IconStash + " Stash",
+	styles.IconTree + " Worktrees",
 	styles.IconGear + " CI",
+	styles.IconTag + " Releases",
 	styles.IconPR + "  PRs",
 	styles.IconEye + "  Reviews",
 	styles.IconOrg + "  Org",
 }
 
+// backoffMsg reports that a request of client started backing off before a
+// retry; retryTickMsg redraws the countdown to the retry once a second.
+type backoffMsg struct {
+	client *gh.Client
+}
+
+type retryTickMsg struct {
+	client *gh.Client
+}
+
 type Model struct {
-	repo        *git.Repo
-	owner       string
-	repoName    string
-	ghClient    *gh.Client
-	token       string
-	currentView View
-	authModel   auth.Model
-	branchModel branches.Model
-	ciModel     ci.Model
-	prModel     pr.Model
-	reviewModel review.Model
-	orgModel    org.Model
-	reflogModel reflog.Model
-	width       int
-	height      int
-	ready       bool
+	repo          *git.Repo
+	host          string
+	owner         string
+	repoName      string
+	ghClient      *gh.Client
+	token         string
+	tokenInfo     gh.TokenInfo
+	currentView   View
+	prevView      View
+	authModel     auth.Model
+	branchModel   branches.Model
+	statusModel   status.Model
+	stashModel    stash.Model
+	worktreeModel worktrees.Model
+	ciModel       ci.Model
+	releaseModel  releases.Model
+	prModel       pr.Model
+	reviewModel   review.Model
+	orgModel      org.Model
+	reflogModel   reflog.Model
+	conflictModel conflict.Model
+	commitModel   commit.Model
+	logModel      commits.Model
+	rebaseModel   rebase.Model
+	compareModel  compare.Model
+	subModel      submodules.Model
+	diagModel     diagnostics.Model
+	head          git.HeadState
+	bare          bool
+	upstreamURL   string
+	retryClock    *gh.Client // client whose retry countdown is ticking
+	width         int
+	height        int
+	ready         bool
 }
 
-func NewModel(repo *git.Repo, owner, repoName string) Model {
-	return Model{
-		repo:        repo,
-		owner:       owner,
-		repoName:    repoName,
-		currentView: ViewAuth,
-		authModel:   auth.New(),
-		branchModel: branches.New(repo),
-		prModel:     pr.New(),
-		reviewModel: review.New(),
-		reflogModel: reflog.New(repo),
+func NewModel(repo *git.Repo, host, owner, repoName string) Model {
+	if host == "" {
+		host = gh.DefaultHost()
 	}
+	m := Model{
+		repo:          repo,
+		host:          host,
+		owner:         owner,
+		repoName:      repoName,
+		currentView:   ViewAuth,
+		authModel:     auth.New(host, repo == nil),
+		branchModel:   branches.New(repo),
+		statusModel:   status.New(repo),
+		stashModel:    stash.New(repo),
+		worktreeModel: worktrees.New(repo),
+		releaseModel:  releases.New(repo, nil),
+		subModel:      submodules.New(repo),
+		prModel:       pr.New(nil),
+		reviewModel:   review.New(),
+		reflogModel:   reflog.New(repo),
+		conflictModel: conflict.New(repo),
+	}
+	m.loadRepoInfo()
+	return m
 }
 
 func (m Model) Init() tea.Cmd {
@@ -85,11 +173,38 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 
 	case tea.KeyMsg:
+		if m.currentView == ViewAuth && msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		if m.currentView != ViewAuth && m.reflogModel.IsActive() {
+			if !m.reflogModel.IsPrompting() {
+				if cmd, handled := HandleGlobalKeys(msg); handled {
+					return m, cmd
+				}
+			}
+			var cmd tea.Cmd
+			m.reflogModel, cmd = m.reflogModel.Update(msg)
+			return m, cmd
+		}
 		if m.currentView != ViewAuth {
-			if m.currentView == ViewOrg && m.orgModel.IsOverlayActive() {
-				// let the org model handle all keys when clone overlay is open
+			if m.currentView == ViewOrg && m.orgModel.IsInputActive() {
+				// let the org model handle all keys while cloning, searching or filtering
 			} else if m.currentView == ViewBranches && m.branchModel.IsInputActive() {
-				// let the branch model handle all keys when creating a branch
+				// let the branch model handle all keys while typing, filtering or confirming
+			} else if m.currentView == ViewStatus && m.statusModel.IsConfirming() {
+				// let the status model handle all keys while confirming a discard
+			} else if m.currentView == ViewStash && m.stashModel.IsInputActive() {
+				// let the stash model handle all keys while typing or confirming
+			} else if m.currentView == ViewWorktrees && m.worktreeModel.IsInputActive() {
+				// let the worktrees model handle all keys while typing or confirming
+			} else if m.currentView == ViewReleases && m.releaseModel.IsInputActive() {
+				// let the releases model handle all keys while tagging
+			} else if m.currentView == ViewCompare && m.compareModel.IsInputActive() {
+				// let the compare model handle all keys while editing refs
+			} else if m.currentView == ViewLog && m.logModel.IsInputActive() {
+				// let the log model handle all keys while naming a branch
+			} else if m.currentView == ViewCommit {
+				// the commit composer is a text editor
 			} else if cmd, handled := HandleGlobalKeys(msg); handled {
 				return m, cmd
 			}
@@ -98,23 +213,206 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case SwitchViewMsg:
 		return m, m.handleViewSwitch(msg)
 
-	case reflog.RefreshReflogMsg:
+	case reflog.RefreshReflogMsg, reflog.FocusMsg, reflog.UndoMsg:
+		if m.repo == nil {
+			return m, nil
+		}
+		if _, ok := msg.(reflog.RefreshReflogMsg); ok {
+			m.loadRepoInfo()
+		}
 		var cmd tea.Cmd
 		m.reflogModel, cmd = m.reflogModel.Update(msg)
 		return m, cmd
 
+	case reflog.ChangedMsg:
+		return m, m.initView()
+
+	case conflict.OpenMsg:
+		m.conflictModel = conflict.New(m.repo)
+		m.currentView = ViewConflicts
+		cmd := m.propagateSize(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+		return m, tea.Batch(cmd, m.conflictModel.Init())
+
+	case conflict.ResolvedMsg, conflict.CloseMsg:
+		m.currentView = ViewBranches
+		return m, tea.Batch(m.branchModel.Init(), m.reflogModel.Init())
+
+	case commit.OpenMsg:
+		m.commitModel = commit.New(m.repo)
+		m.currentView = ViewCommit
+		cmd := m.propagateSize(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+		return m, tea.Batch(cmd, m.commitModel.Init())
+
+	case commit.DoneMsg:
+		m.currentView = ViewStatus
+		if msg.Committed {
+			return m, tea.Batch(m.statusModel.Init(), m.branchModel.Init())
+		}
+		return m, m.statusModel.Init()
+
+	case commits.OpenMsg:
+		m.logModel = commits.New(m.repo, msg.Branch)
+		m.currentView = ViewLog
+		cmd := m.propagateSize(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+		return m, tea.Batch(cmd, m.logModel.Init())
+
+	case commits.CloseMsg:
+		m.currentView = ViewBranches
+		return m, m.branchModel.Init()
+
+	case compare.OpenMsg:
+		m.compareModel = compare.New(m.repo, msg.Base, msg.Head)
+		m.currentView = ViewCompare
+		cmd := m.propagateSize(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+		return m, tea.Batch(cmd, m.compareModel.Init())
+
+	case compare.CloseMsg:
+		m.currentView = ViewBranches
+		return m, m.branchModel.Init()
+
+	case submodules.OpenMsg:
+		m.subModel = submodules.New(m.repo)
+		m.currentView = ViewSubmodules
+		cmd := m.propagateSize(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+		return m, tea.Batch(cmd, m.subModel.Init())
+
+	case submodules.CloseMsg:
+		m.currentView = ViewBranches
+		return m, m.branchModel.Init()
+
+	case rebase.OpenMsg:
+		m.rebaseModel = rebase.New(m.repo)
+		m.currentView = ViewRebase
+		cmd := m.propagateSize(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+		return m, tea.Batch(cmd, m.rebaseModel.Init())
+
+	case rebase.DoneMsg:
+		m.currentView = ViewBranches
+		return m, m.branchModel.Init()
+
+	case worktrees.SwitchMsg:
+		return m, m.switchRepo(msg.Repo)
+
+	case org.ClonedMsg:
+		if m.repo != nil {
+			return m, nil
+		}
+		repo, err := git.Open(msg.Path)
+		if err != nil || os.Chdir(msg.Path) != nil {
+			return m, nil
+		}
+		return m, m.switchRepo(repo)
+
+	case auth.OpenMsg:
+		if m.token == "" {
+			return m, nil
+		}
+		if m.currentView != ViewDiagnostics {
+			m.prevView = m.currentView
+		}
+		m.authModel = auth.NewAccount(m.host, m.tokenInfo.Login, m.repo == nil)
+		m.currentView = ViewAuth
+		cmd := m.propagateSize(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+		return m, tea.Batch(cmd, m.authModel.Init())
+
+	case auth.CancelMsg:
+		m.currentView = m.prevView
+		return m, nil
+
+	case auth.LoggedOutMsg:
+		m.token = ""
+		m.tokenInfo = gh.TokenInfo{}
+		m.ghClient = nil
+		m.prModel = pr.New(nil)
+		m.orgModel = org.New(nil)
+		if m.repo != nil {
+			m.ciModel = ci.New(nil, m.repo.CurrentBranch())
+			m.releaseModel = releases.New(m.repo, nil)
+		}
+		// Show the sign-in screen without picking up another token on its own
+		m.authModel = auth.New(m.host, m.repo == nil)
+		host := m.host
+		cmd := m.propagateSize(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+		return m, tea.Batch(cmd, func() tea.Msg { return gh.AuthStatus{Host: host} })
+
+	case diagnostics.OpenMsg:
+		if m.ghClient == nil {
+			return m, nil
+		}
+		if m.currentView != ViewAuth {
+			m.prevView = m.currentView
+		}
+		m.currentView = ViewDiagnostics
+		cmd := m.propagateSize(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+		return m, cmd
+
+	case diagnostics.CloseMsg:
+		m.currentView = m.prevView
+		return m, nil
+
+	case diagnostics.CheckedMsg:
+		var cmd tea.Cmd
+		m.diagModel, cmd = m.diagModel.Update(msg)
+		return m, cmd
+
+	case backoffMsg:
+		if msg.client != m.ghClient {
+			return m, nil
+		}
+		if m.retryClock == msg.client {
+			return m, waitForBackoff(msg.client)
+		}
+		m.retryClock = msg.client
+		return m, tea.Batch(waitForBackoff(msg.client), retryTick(msg.client))
+
+	case retryTickMsg:
+		if msg.client != m.retryClock {
+			return m, nil
+		}
+		if msg.client != m.ghClient || !msg.client.RateLimit().Waiting() {
+			m.retryClock = nil
+			return m, nil
+		}
+		return m, retryTick(msg.client)
+
+	case branches.LoadedMsg, branches.AheadBehindMsg, branches.SubmodulesCheckedMsg:
+		var cmd tea.Cmd
+		m.branchModel, cmd = m.branchModel.Update(msg)
+		return m, cmd
+
 	case auth.AuthDoneMsg:
+		// With a session running this switches accounts in place
+		switching := m.token != ""
+		m.host = msg.Host
 		m.token = msg.Token
-		client, err := gh.NewClient(m.owner, m.repoName, msg.Token)
+		m.tokenInfo = msg.Info
+		client, err := gh.NewClient(m.host, m.owner, m.repoName, msg.Token)
+		var diagCmd tea.Cmd
 		if err == nil {
 			m.ghClient = client
-			m.ciModel = ci.New(client, m.repo.CurrentBranch())
 			m.orgModel = org.New(client)
+			m.diagModel = diagnostics.NewStartup(client, msg.Info)
+			diagCmd = tea.Batch(m.diagModel.Init(), waitForBackoff(client))
+		}
+		if m.repo == nil {
+			m.currentView = ViewOrg
+			cmd := m.propagateSize(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+			return m, tea.Batch(cmd, m.initView(), diagCmd)
+		}
+		if err == nil {
+			m.ciModel = ci.New(client, m.repo.CurrentBranch())
+			m.releaseModel = releases.New(m.repo, client)
+			m.prModel = pr.New(client)
+		}
+		if switching {
+			m.currentView = m.prevView
+			cmd := m.propagateSize(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+			return m, tea.Batch(cmd, m.initView(), diagCmd)
 		}
 		m.currentView = ViewBranches
-		cmds := []tea.Cmd{m.branchModel.Init(), m.reflogModel.Init()}
+		cmds := []tea.Cmd{m.propagateSize(tea.WindowSizeMsg{Width: m.width, Height: m.height}), m.branchModel.Init(), m.reflogModel.Init()}
 		if err == nil {
-			cmds = append(cmds, m.ciModel.Init())
+			cmds = append(cmds, m.ciModel.Init(), diagCmd)
 		}
 		return m, tea.Batch(cmds...)
 	}
@@ -128,9 +426,21 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case ViewBranches:
 		m.branchModel, cmd = m.branchModel.Update(msg)
 		cmds = append(cmds, cmd)
+	case ViewStatus:
+		m.statusModel, cmd = m.statusModel.Update(msg)
+		cmds = append(cmds, cmd)
+	case ViewStash:
+		m.stashModel, cmd = m.stashModel.Update(msg)
+		cmds = append(cmds, cmd)
+	case ViewWorktrees:
+		m.worktreeModel, cmd = m.worktreeModel.Update(msg)
+		cmds = append(cmds, cmd)
 	case ViewCI:
 		m.ciModel, cmd = m.ciModel.Update(msg)
 		cmds = append(cmds, cmd)
+	case ViewReleases:
+		m.releaseModel, cmd = m.releaseModel.Update(msg)
+		cmds = append(cmds, cmd)
 	case ViewPR:
 		m.prModel, cmd = m.prModel.Update(msg)
 		cmds = append(cmds, cmd)
@@ -140,10 +450,31 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case ViewOrg:
 		m.orgModel, cmd = m.orgModel.Update(msg)
 		cmds = append(cmds, cmd)
+	case ViewConflicts:
+		m.conflictModel, cmd = m.conflictModel.Update(msg)
+		cmds = append(cmds, cmd)
+	case ViewCommit:
+		m.commitModel, cmd = m.commitModel.Update(msg)
+		cmds = append(cmds, cmd)
+	case ViewLog:
+		m.logModel, cmd = m.logModel.Update(msg)
+		cmds = append(cmds, cmd)
+	case ViewRebase:
+		m.rebaseModel, cmd = m.rebaseModel.Update(msg)
+		cmds = append(cmds, cmd)
+	case ViewCompare:
+		m.compareModel, cmd = m.compareModel.Update(msg)
+		cmds = append(cmds, cmd)
+	case ViewSubmodules:
+		m.subModel, cmd = m.subModel.Update(msg)
+		cmds = append(cmds, cmd)
+	case ViewDiagnostics:
+		m.diagModel, cmd = m.diagModel.Update(msg)
+		cmds = append(cmds, cmd)
 	}
 
 	// Forward non-key messages to reflog pane
-	if _, isKey := msg.(tea.KeyMsg); !isKey && m.currentView != ViewAuth {
+	if _, isKey := msg.(tea.KeyMsg); !isKey && m.currentView != ViewAuth && m.repo != nil {
 		m.reflogModel, cmd = m.reflogModel.Update(msg)
 		cmds = append(cmds, cmd)
 	}
@@ -169,17 +500,87 @@ func (m Model) View() string {
 		content = m.branchModel.View()
 		if m.branchModel.IsConfirming() {
 			hints = formatHints([][]string{{"y", "rename remote"}, {"n", "local only"}, {"esc", "cancel"}})
+		} else if m.branchModel.IsConfirmingStash() {
+			hints = formatHints([][]string{{"y", "stash and checkout"}, {"n", "cancel"}})
 		} else if m.branchModel.IsInputActive() {
 			hints = formatHints([][]string{{"enter", "confirm"}, {"esc", "cancel"}})
 		} else {
-			hints = formatHints([][]string{{"enter", "checkout"}, {"p", "push"}, {"a", "new branch"}, {"R", "rename"}, {"r", "refresh"}, {"/", "filter"}, {"tab", "next view"}, {"q", "quit"}})
+			hints = formatHints([][]string{{"enter", "checkout"}, {"p", "push"}, {"a", "new branch"}, {"R", "rename"}, {"l", "log"}, {"c", "compare"}, {"S", "submodules"}, {"i", "interactive rebase"}, {"b", "rebase on default"}, {"m", "merge default"}, {"r", "refresh"}, {"/", "filter"}, {"tab", "next view"}, {"q", "quit"}})
+		}
+	case ViewConflicts:
+		content = m.conflictModel.View()
+		pairs := [][]string{{"enter", "edit"}, {"m", "mark resolved"}, {"c", "continue"}}
+		if m.conflictModel.Operation().CanSkip() {
+			pairs = append(pairs, []string{"s", "skip"})
+		}
+		pairs = append(pairs, []string{"a", "abort"}, []string{"esc", "back"}, []string{"q", "quit"})
+		hints = formatHints(pairs)
+	case ViewStatus:
+		content = m.statusModel.View()
+		if m.statusModel.IsConfirming() {
+			hints = formatHints([][]string{{"y", "discard"}, {"n", "cancel"}})
+		} else if m.statusModel.IsDiffFocused() {
+			hints = formatHints([][]string{{"↑/↓", "hunk"}, {"space", "stage/unstage hunk"}, {"d", "discard hunk"}, {"esc", "files"}, {"q", "quit"}})
+		} else {
+			hints = formatHints([][]string{{"↑/↓", "file"}, {"space", "stage/unstage"}, {"a", "stage all"}, {"d", "discard"}, {"enter", "hunks"}, {"c", "commit"}, {"r", "refresh"}, {"tab", "next view"}, {"q", "quit"}})
+		}
+	case ViewStash:
+		content = m.stashModel.View()
+		if m.stashModel.IsConfirming() {
+			hints = formatHints([][]string{{"y", "drop"}, {"n", "cancel"}})
+		} else if m.stashModel.IsInputActive() {
+			hints = formatHints([][]string{{"enter", "confirm"}, {"esc", "cancel"}})
+		} else {
+			hints = formatHints([][]string{{"a", "apply"}, {"p", "pop"}, {"d", "drop"}, {"s", "stash changes"}, {"pgup/pgdn", "scroll diff"}, {"r", "refresh"}, {"/", "filter"}, {"tab", "next view"}, {"q", "quit"}})
+		}
+	case ViewWorktrees:
+		content = m.worktreeModel.View()
+		if m.worktreeModel.IsConfirming() {
+			hints = formatHints([][]string{{"y", "remove"}, {"n", "cancel"}})
+		} else if m.worktreeModel.IsInputActive() {
+			hints = formatHints([][]string{{"enter", "confirm"}, {"esc", "cancel"}})
+		} else {
+			hints = formatHints([][]string{{"enter", "open"}, {"a", "add for branch"}, {"P", "add for PR"}, {"d", "remove"}, {"p", "prune"}, {"r", "refresh"}, {"/", "filter"}, {"tab", "next view"}, {"q", "quit"}})
+		}
+	case ViewLog:
+		content = m.logModel.View()
+		if m.logModel.IsInputActive() {
+			hints = formatHints([][]string{{"enter", "confirm"}, {"esc", "cancel"}})
+		} else {
+			hints = formatHints([][]string{{"↑/↓", "scroll"}, {"space", "mark"}, {"p", "cherry-pick here"}, {"P", "cherry-pick to new branch"}, {"c", "compare to default"}, {"r", "refresh"}, {"esc", "back"}, {"q", "quit"}})
 		}
+	case ViewRebase:
+		content = m.rebaseModel.View()
+		hints = formatHints([][]string{{"p/r/e/s/f/d", "pick/reword/edit/squash/fixup/drop"}, {"space", "cycle"}, {"J/K", "move"}, {"enter", "run"}, {"esc", "cancel"}})
+	case ViewCompare:
+		content = m.compareModel.View()
+		if m.compareModel.IsInputActive() {
+			hints = formatHints([][]string{{"enter", "confirm"}, {"esc", "cancel"}})
+		} else {
+			hints = formatHints([][]string{{"↑/↓", "scroll"}, {"e", "edit refs"}, {"s", "swap"}, {"d", "toggle diff"}, {"r", "refresh"}, {"esc", "back"}, {"q", "quit"}})
+		}
+	case ViewSubmodules:
+		content = m.subModel.View()
+		hints = formatHints([][]string{{"enter", "open in hit"}, {"s", "init/update"}, {"S", "update all"}, {"r", "refresh"}, {"esc", "back"}, {"q", "quit"}})
+	case ViewDiagnostics:
+		content = m.diagModel.View()
+		hints = formatHints([][]string{{"enter", "continue"}, {"A", "switch token"}, {"q", "quit"}})
+	case ViewCommit:
+		content = m.commitModel.View()
+		hints = formatHints([][]string{{"ctrl+s", "commit"}, {"ctrl+o", "$EDITOR"}, {"tab", "next field"}, {"space", "toggle"}, {"esc", "cancel"}})
 	case ViewCI:
 		content = m.ciModel.View()
 		hints = formatHints([][]string{{"enter", "details"}, {"esc", "back"}, {"r", "refresh"}, {"tab", "next view"}, {"q", "quit"}})
+	case ViewReleases:
+		content = m.releaseModel.View()
+		if m.releaseModel.IsInputActive() {
+			hints = formatHints([][]string{{"enter", "confirm"}, {"esc", "cancel"}})
+		} else {
+			hints = formatHints([][]string{{"t", "new tag"}, {"p", "push tag"}, {"n", "draft release"}, {"pgup/pgdn", "scroll notes"}, {"r", "refresh"}, {"/", "filter"}, {"tab", "next view"}, {"q", "quit"}})
+		}
 	case ViewPR:
 		content = m.prModel.View()
-		hints = formatHints([][]string{{"tab", "next view"}, {"q", "quit"}})
+		hints = formatHints([][]string{{"r", "refresh"}, {"tab", "next view"}, {"q", "quit"}})
 	case ViewReview:
 		content = m.reviewModel.View()
 		hints = formatHints([][]string{{"tab", "next view"}, {"q", "quit"}})
@@ -187,15 +588,30 @@ func (m Model) View() string {
 		content = m.orgModel.View()
 		if m.orgModel.IsOverlayActive() {
 			hints = formatHints([][]string{{"enter", "clone"}, {"esc", "cancel"}})
+		} else if m.orgModel.IsInputActive() {
+			hints = formatHints([][]string{{"enter", "confirm"}, {"esc", "cancel"}})
 		} else {
-			hints = formatHints([][]string{{"enter", "select"}, {"esc", "back"}, {"r", "refresh"}, {"tab", "next view"}, {"q", "quit"}})
+			hints = formatHints([][]string{{"enter", "select"}, {"esc", "back"}, {"s", "search"}, {"/", "filter"}, {"r", "refresh"}, {"tab", "next view"}, {"q", "quit"}})
 		}
 	}
+	if m.reflogModel.IsActive() {
+		if m.reflogModel.IsPrompting() {
+			hints = formatHints([][]string{{"y", "confirm"}, {"n", "cancel"}})
+		} else {
+			hints = formatHints([][]string{{"↑/↓", "select"}, {"s/m/h", "reset soft/mixed/hard"}, {"b", "branch here"}, {"d", "checkout detached"}, {"u", "undo last"}, {"esc", "leave reflog"}})
+		}
+	}
+	if rate := m.renderRateLimit(); rate != "" {
+		hints += "   " + rate
+	}
 	footer := styles.StatusBarStyle.Render(hints)
 
 	contentHeight := m.height - lipgloss.Height(tabBar) - lipgloss.Height(repoInfo) - lipgloss.Height(footer)
 
-	showReflog := m.width >= 200
+	showReflog := m.width >= reflogMinWidth && m.repo != nil
+	if !showReflog && m.reflogModel.IsActive() {
+		content = m.reflogModel.View()
+	}
 	if showReflog {
 		mainWidth := m.width - reflogPaneWidth
 		mainContent := lipgloss.NewStyle().Width(mainWidth).Height(contentHeight).Render(content)
@@ -208,11 +624,22 @@ func (m Model) View() string {
 	return lipgloss.JoinVertical(lipgloss.Left, tabBar, repoInfo, content, footer)
 }
 
+// activeTab is the tab the current view belongs to.
+func (m Model) activeTab() View {
+	if m.currentView == ViewDiagnostics {
+		return m.prevView.tab()
+	}
+	return m.currentView.tab()
+}
+
 func (m Model) renderTabBar() string {
 	var tabs []string
 	for i, name := range tabNames {
 		v := View(i + 1)
-		if v == m.currentView {
+		if m.repo == nil && v != ViewOrg {
+			continue
+		}
+		if v == m.activeTab() {
 			tabs = append(tabs, styles.ActiveTabStyle.Render(name))
 		} else {
 			tabs = append(tabs, styles.InactiveTabStyle.Render(name))
@@ -221,31 +648,96 @@ func (m Model) renderTabBar() string {
 	return styles.TabBarStyle.Render(strings.Join(tabs, " "))
 }
 
+// renderRateLimit shows the remaining GitHub API quota, or how long a
+// request is backing off after a rate limit or a server error.
+func (m Model) renderRateLimit() string {
+	if m.ghClient == nil {
+		return ""
+	}
+	rate := m.ghClient.RateLimit()
+	switch {
+	case rate.Waiting():
+		wait := time.Until(rate.RetryAt).Round(time.Second)
+		return styles.BadgePending.Render(fmt.Sprintf("%s · retrying in %s", rate.RetryReason, wait))
+	case rate.Low():
+		return styles.BadgePending.Render(fmt.Sprintf("API %d/%d · resets %s", rate.Remaining, rate.Limit, rate.Reset.Format("15:04")))
+	case rate.Known():
+		return styles.SubtitleStyle.Render(fmt.Sprintf("API %d/%d", rate.Remaining, rate.Limit))
+	}
+	return ""
+}
+
+// waitForBackoff delivers the next backoff of client's requests.
+func waitForBackoff(client *gh.Client) tea.Cmd {
+	return func() tea.Msg {
+		<-client.Backoffs()
+		return backoffMsg{client: client}
+	}
+}
+
+func retryTick(client *gh.Client) tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg { return retryTickMsg{client: client} })
+}
+
 func (m Model) renderRepoInfo() string {
+	if m.repo == nil {
+		return lipgloss.NewStyle().MarginLeft(1).Render(styles.SubtitleStyle.Render("Not in a git repository · clone one to open it"))
+	}
+
 	name := lipgloss.NewStyle().Bold(true).Foreground(styles.ColorSecondary).Render(m.owner + "/" + m.repoName)
 	path := styles.SubtitleStyle.Render(m.repo.Path())
 
 	parts := []string{name, path}
 
-	branch := m.repo.CurrentBranch()
-	if branch != "" && m.repo.HasUpstream(branch) {
-		remote := styles.SubtitleStyle.Render(m.repo.RemoteURL())
-		parts = append(parts, remote)
+	switch {
+	case m.bare:
+		parts = append(parts, styles.BadgeNeutral.Render("bare"))
+	case m.head.Detached:
+		parts = append(parts, styles.BadgePending.Render("detached at "+m.head.Hash))
+	case m.head.Unborn:
+		parts = append(parts, styles.BadgePending.Render("no commits yet"))
+	}
+
+	if m.upstreamURL != "" {
+		parts = append(parts, styles.SubtitleStyle.Render(m.upstreamURL))
 	}
 
 	sep := styles.SubtitleStyle.Render("  ")
 	return lipgloss.NewStyle().MarginLeft(1).Render(strings.Join(parts, sep))
 }
 
+// loadRepoInfo reads the HEAD and upstream state shown above the tabs, so
+// rendering never has to ask git. It runs whenever a view (re)loads and
+// whenever HEAD may have moved.
+func (m *Model) loadRepoInfo() {
+	m.head, m.bare, m.upstreamURL = git.HeadState{}, false, ""
+	if m.repo == nil {
+		return
+	}
+	m.head = m.repo.Head()
+	m.bare = m.repo.IsBare()
+	if m.head.Detached || m.head.Branch == "" {
+		return
+	}
+	if upstream := m.repo.UpstreamRef(m.head.Branch); upstream != "" {
+		name, _, _ := strings.Cut(upstream, "/")
+		m.upstreamURL = m.repo.RemoteURL(name)
+	}
+}
+
 func (m *Model) handleViewSwitch(msg SwitchViewMsg) tea.Cmd {
+	if m.repo == nil {
+		// Only the Org view works without a repository
+		return nil
+	}
 	if msg.View == -1 {
-		next := int(m.currentView) + 1
+		next := int(m.activeTab()) + 1
 		if next > int(ViewOrg) {
 			next = int(ViewBranches)
 		}
 		m.currentView = View(next)
 	} else if msg.View == -2 {
-		prev := int(m.currentView) - 1
+		prev := int(m.activeTab()) - 1
 		if prev < int(ViewBranches) {
 			prev = int(ViewOrg)
 		}
@@ -253,15 +745,30 @@ func (m *Model) handleViewSwitch(msg SwitchViewMsg) tea.Cmd {
 	} else {
 		m.currentView = msg.View
 	}
+	return m.initView()
+}
 
+// initView (re)loads the current view's data.
+func (m *Model) initView() tea.Cmd {
+	m.loadRepoInfo()
 	switch m.currentView {
 	case ViewBranches:
 		return m.branchModel.Init()
+	case ViewStatus:
+		return m.statusModel.Init()
+	case ViewStash:
+		return m.stashModel.Init()
+	case ViewWorktrees:
+		return m.worktreeModel.Init()
 	case ViewCI:
 		if m.ghClient != nil {
 			m.ciModel = ci.New(m.ghClient, m.repo.CurrentBranch())
 			return m.ciModel.Init()
 		}
+	case ViewReleases:
+		return m.releaseModel.Init()
+	case ViewPR:
+		return m.prModel.Init()
 	case ViewOrg:
 		if m.ghClient != nil {
 			return m.orgModel.Init()
@@ -270,6 +777,58 @@ func (m *Model) handleViewSwitch(msg SwitchViewMsg) tea.Cmd {
 	return nil
 }
 
+// switchRepo reopens every repository-bound view on repo, keeping the
+// GitHub session, and lands on the Branches view. A repository on another
+// host goes through sign-in first, as at startup, since tokens are per host.
+func (m *Model) switchRepo(repo *git.Repo) tea.Cmd {
+	m.repo = repo
+	m.loadRepoInfo()
+	host, owner, name, err := repo.GitHubRepo()
+	if err != nil {
+		host, owner, name = gh.DefaultHost(), "", ""
+	}
+	m.owner, m.repoName = owner, name
+	m.branchModel = branches.New(repo)
+	m.statusModel = status.New(repo)
+	m.stashModel = stash.New(repo)
+	m.worktreeModel = worktrees.New(repo)
+	m.reflogModel = reflog.New(repo)
+	m.conflictModel = conflict.New(repo)
+	m.releaseModel = releases.New(repo, nil)
+	m.subModel = submodules.New(repo)
+	m.prModel = pr.New(nil)
+	m.ciModel = ci.New(nil, repo.CurrentBranch())
+	if host != m.host {
+		m.host = host
+		m.token = ""
+		m.tokenInfo = gh.TokenInfo{}
+		m.ghClient = nil
+		m.orgModel = org.New(nil)
+		m.authModel = auth.New(host, false)
+		m.currentView = ViewAuth
+		cmd := m.propagateSize(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+		return tea.Batch(cmd, m.authModel.Init())
+	}
+	if m.token != "" {
+		m.ghClient = nil
+		if client, err := gh.NewClient(m.host, m.owner, m.repoName, m.token); err == nil {
+			m.ghClient = client
+			m.ciModel = ci.New(client, repo.CurrentBranch())
+			m.releaseModel = releases.New(repo, client)
+			m.prModel = pr.New(client)
+			m.orgModel = org.New(client)
+			m.diagModel = diagnostics.New(client, m.tokenInfo)
+		}
+	}
+	m.currentView = ViewBranches
+	cmd := m.propagateSize(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+	cmds := []tea.Cmd{cmd, m.branchModel.Init(), m.reflogModel.Init()}
+	if m.ghClient != nil {
+		cmds = append(cmds, m.diagModel.Init(), waitForBackoff(m.ghClient))
+	}
+	return tea.Batch(cmds...)
+}
+
 func formatHints(pairs [][]string) string {
 	key := lipgloss.NewStyle().Foreground(styles.ColorText).Bold(true)
 	desc := lipgloss.NewStyle().Foreground(styles.ColorMuted)
@@ -287,7 +846,7 @@ func (m *Model) propagateSize(msg tea.WindowSizeMsg) tea.Cmd {
 	footer := styles.StatusBarStyle.Render("q: quit | tab: next view | esc: back | ?: help")
 	contentHeight := msg.Height - lipgloss.Height(tabBar) - lipgloss.Height(repoInfo) - lipgloss.Height(footer)
 
-	showReflog := msg.Width >= 160
+	showReflog := msg.Width >= reflogMinWidth && m.repo != nil
 	mainWidth := msg.Width
 	if showReflog {
 		mainWidth = msg.Width - reflogPaneWidth
@@ -305,10 +864,18 @@ func (m *Model) propagateSize(msg tea.WindowSizeMsg) tea.Cmd {
 	cmds = append(cmds, cmd)
 	m.branchModel, cmd = m.branchModel.Update(contentMsg)
 	cmds = append(cmds, cmd)
-	if m.ghClient != nil {
+	m.statusModel, cmd = m.statusModel.Update(contentMsg)
+	cmds = append(cmds, cmd)
+	m.stashModel, cmd = m.stashModel.Update(contentMsg)
+	cmds = append(cmds, cmd)
+	m.worktreeModel, cmd = m.worktreeModel.Update(contentMsg)
+	cmds = append(cmds, cmd)
+	if m.ghClient != nil && m.repo != nil {
 		m.ciModel, cmd = m.ciModel.Update(contentMsg)
 		cmds = append(cmds, cmd)
 	}
+	m.releaseModel, cmd = m.releaseModel.Update(contentMsg)
+	cmds = append(cmds, cmd)
 	m.prModel, cmd = m.prModel.Update(contentMsg)
 	cmds = append(cmds, cmd)
 	m.reviewModel, cmd = m.reviewModel.Update(contentMsg)
@@ -317,15 +884,34 @@ func (m *Model) propagateSize(msg tea.WindowSizeMsg) tea.Cmd {
 		m.orgModel, cmd = m.orgModel.Update(contentMsg)
 		cmds = append(cmds, cmd)
 	}
+	m.conflictModel, cmd = m.conflictModel.Update(contentMsg)
+	cmds = append(cmds, cmd)
+	if m.currentView == ViewCommit {
+		m.commitModel, cmd = m.commitModel.Update(contentMsg)
+		cmds = append(cmds, cmd)
+	}
+	m.logModel, cmd = m.logModel.Update(contentMsg)
+	cmds = append(cmds, cmd)
+	m.rebaseModel, cmd = m.rebaseModel.Update(contentMsg)
+	cmds = append(cmds, cmd)
+	m.compareModel, cmd = m.compareModel.Update(contentMsg)
+	cmds = append(cmds, cmd)
+	m.subModel, cmd = m.subModel.Update(contentMsg)
+	cmds = append(cmds, cmd)
+	m.diagModel, cmd = m.diagModel.Update(contentMsg)
+	cmds = append(cmds, cmd)
 
+	// When the pane doesn't fit beside the content, it takes the content
+	// area while focused
+	reflogMsg := tea.WindowSizeMsg{
+		Width:  mainWidth,
+		Height: contentHeight,
+	}
 	if showReflog {
-		reflogMsg := tea.WindowSizeMsg{
-			Width:  reflogPaneWidth,
-			Height: contentHeight,
-		}
-		m.reflogModel, cmd = m.reflogModel.Update(reflogMsg)
-		cmds = append(cmds, cmd)
+		reflogMsg.Width = reflogPaneWidth
 	}
+	m.reflogModel, cmd = m.reflogModel.Update(reflogMsg)
+	cmds = append(cmds, cmd)
 
 	return tea.Batch(cmds...)
 }
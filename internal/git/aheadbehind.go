@@ -0,0 +1,326 @@
+package git
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	cgformat "github.com/go-git/go-git/v5/plumbing/format/commitgraph/v2"
+	"github.com/go-git/go-git/v5/plumbing/object/commitgraph"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+)
+
+// AheadBehindResult carries the sync counts of one branch, as computed by
+// StreamAheadBehind.
+type AheadBehindResult struct {
+	Branch        string
+	RemoteAhead   int
+	RemoteBehind  int
+	DefaultAhead  int
+	DefaultBehind int
+}
+
+// nodeInfo is what the walk needs from a commit. It is cached across walks
+// so that history shared by many branches, typically the default branch, is
+// decoded once.
+type nodeInfo struct {
+	parents    []plumbing.Hash
+	generation uint64
+	when       time.Time
+}
+
+type nodeCache struct {
+	mu    sync.RWMutex
+	nodes map[plumbing.Hash]nodeInfo
+}
+
+func newNodeCache() *nodeCache {
+	return &nodeCache{nodes: make(map[plumbing.Hash]nodeInfo)}
+}
+
+// graphWalker counts commits between two tips. With a commit-graph file,
+// which provides parents and generation numbers without inflating commit
+// objects, it walks in process; without one it asks git, since commit dates
+// alone cannot tell when the walk may stop. Close releases the graph file.
+type graphWalker struct {
+	r     *Repo
+	repo  *gogit.Repository
+	graph cgformat.Index
+	index commitgraph.CommitNodeIndex
+	cache *nodeCache
+}
+
+func newGraphWalker(r *Repo, repo *gogit.Repository, cache *nodeCache) *graphWalker {
+	w := &graphWalker{r: r, repo: repo, cache: cache}
+	if fs, ok := repo.Storer.(*filesystem.Storage); ok {
+		if idx, err := cgformat.OpenChainOrFileIndex(fs.Filesystem()); err == nil {
+			w.graph = idx
+		}
+	}
+	w.index = commitgraph.NewGraphCommitNodeIndex(w.graph, repo.Storer)
+	return w
+}
+
+func (w *graphWalker) Close() error {
+	if w.graph == nil {
+		return nil
+	}
+	return w.graph.Close()
+}
+
+func (w *graphWalker) node(h plumbing.Hash) (nodeInfo, error) {
+	w.cache.mu.RLock()
+	info, ok := w.cache.nodes[h]
+	w.cache.mu.RUnlock()
+	if ok {
+		return info, nil
+	}
+
+	n, err := w.index.Get(h)
+	if err != nil {
+		return nodeInfo{}, err
+	}
+	info = nodeInfo{parents: n.ParentHashes(), generation: n.Generation(), when: n.CommitTime()}
+	w.cache.mu.Lock()
+	w.cache.nodes[h] = info
+	w.cache.mu.Unlock()
+	return info, nil
+}
+
+func (w *graphWalker) resolve(rev string) (plumbing.Hash, bool) {
+	h, err := w.repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return plumbing.ZeroHash, false
+	}
+	return *h, true
+}
+
+const (
+	flagLeft uint8 = 1 << iota
+	flagRight
+	flagBoth = flagLeft | flagRight
+)
+
+// count returns how many commits are reachable only from a and only from b,
+// like `git rev-list --left-right --count a...b`. Commits are visited
+// children before parents, by generation number, and the walk stops once
+// every queued commit is reachable from both sides. Commits missing from the
+// commit-graph, newer than it, are visited first by commit date, which can
+// be wrong when clocks were skewed: a commit that picks up a new flag after
+// it was visited is walked again, and the walk does not stop while such
+// commits are queued.
+func (w *graphWalker) count(a, b plumbing.Hash) (int, int, error) {
+	if a == b {
+		return 0, 0, nil
+	}
+	if w.graph == nil {
+		return w.r.revListCount(a.String(), b.String())
+	}
+
+	flags := make(map[plumbing.Hash]uint8)
+	queued := make(map[plumbing.Hash]bool)
+	done := make(map[plumbing.Hash]bool)
+	revisit := make(map[plumbing.Hash]bool)
+	queue := &walkQueue{}
+	active, revisits := 0, 0
+
+	push := func(h plumbing.Hash, f uint8) error {
+		old := flags[h]
+		if old|f == old {
+			return nil
+		}
+		flags[h] = old | f
+		if queued[h] {
+			if old|f == flagBoth && !revisit[h] {
+				active--
+			}
+			return nil
+		}
+		info, err := w.node(h)
+		if err != nil {
+			return err
+		}
+		queued[h] = true
+		heap.Push(queue, walkEntry{hash: h, info: info})
+		switch {
+		case done[h]:
+			revisit[h] = true
+			revisits++
+		case old|f != flagBoth:
+			active++
+		}
+		return nil
+	}
+
+	if err := push(a, flagLeft); err != nil {
+		return 0, 0, err
+	}
+	if err := push(b, flagRight); err != nil {
+		return 0, 0, err
+	}
+
+	for queue.Len() > 0 && (active > 0 || revisits > 0 || !(*queue)[0].info.ordered()) {
+		e := heap.Pop(queue).(walkEntry)
+		delete(queued, e.hash)
+		if revisit[e.hash] {
+			delete(revisit, e.hash)
+			revisits--
+		} else if flags[e.hash] != flagBoth {
+			active--
+		}
+		done[e.hash] = true
+
+		for _, p := range e.info.parents {
+			if err := push(p, flags[e.hash]); err != nil {
+				return 0, 0, err
+			}
+		}
+	}
+
+	ahead, behind := 0, 0
+	for _, f := range flags {
+		switch f {
+		case flagLeft:
+			ahead++
+		case flagRight:
+			behind++
+		}
+	}
+	return ahead, behind, nil
+}
+
+// ordered reports whether the commit has a generation number from the
+// commit-graph. Commits outside it report the maximum, and graphs written by
+// old git versions report zero.
+func (n nodeInfo) ordered() bool {
+	return n.generation != 0 && n.generation != math.MaxUint64
+}
+
+type walkEntry struct {
+	hash plumbing.Hash
+	info nodeInfo
+}
+
+// walkQueue is a max-heap ordered by generation number, then commit date.
+type walkQueue []walkEntry
+
+func (q walkQueue) Len() int { return len(q) }
+func (q walkQueue) Less(i, j int) bool {
+	if q[i].info.generation != q[j].info.generation {
+		return q[i].info.generation > q[j].info.generation
+	}
+	return q[i].info.when.After(q[j].info.when)
+}
+func (q walkQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *walkQueue) Push(x any)   { *q = append(*q, x.(walkEntry)) }
+func (q *walkQueue) Pop() any {
+	old := *q
+	e := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return e
+}
+
+// StreamAheadBehind computes the upstream and default branch counts of
+// branches on a bounded pool of workers and delivers each result as soon as
+// it is ready. The channel is buffered for every branch, so abandoning it
+// doesn't leak the workers, and it is closed when all branches are done.
+func (r *Repo) StreamAheadBehind(branches []Branch) <-chan AheadBehindResult {
+	results := make(chan AheadBehindResult, len(branches))
+	jobs := make(chan Branch)
+	cache := newNodeCache()
+
+	workers := min(runtime.NumCPU(), 8, max(len(branches), 1))
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Each worker reads through its own repository handle, as
+			// go-git's object storage isn't safe for concurrent use.
+			repo := r.repo
+			if own, err := r.reopen(); err == nil {
+				repo = own
+			}
+			w := newGraphWalker(r, repo, cache)
+			defer w.Close()
+			for b := range jobs {
+				results <- w.branchCounts(b)
+			}
+		}()
+	}
+
+	go func() {
+		for _, b := range branches {
+			jobs <- b
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
+
+func (w *graphWalker) branchCounts(b Branch) AheadBehindResult {
+	res := AheadBehindResult{Branch: b.Name}
+	local, ok := w.resolve(plumbing.NewBranchReferenceName(b.Name).String())
+	if !ok {
+		return res
+	}
+	if b.Upstream != "" {
+		if up, ok := w.resolve("refs/remotes/" + b.Upstream); ok {
+			res.RemoteAhead, res.RemoteBehind, _ = w.count(local, up)
+		}
+	}
+	if b.DefaultRef != "" && !b.IsDefault {
+		if def, ok := w.resolve("refs/remotes/" + b.DefaultRef); ok {
+			res.DefaultAhead, res.DefaultBehind, _ = w.count(local, def)
+		}
+	}
+	return res
+}
+
+// revListCount has git count the commits only reachable from a and only
+// reachable from b.
+func (r *Repo) revListCount(a, b string) (int, int, error) {
+	return r.revListCountIn("", a, b)
+}
+
+// revListCountIn is revListCount for the repository in dir, such as a
+// submodule; an empty dir means r itself.
+func (r *Repo) revListCountIn(dir, a, b string) (int, int, error) {
+	args := []string{"rev-list", "--left-right", "--count", a + "..." + b, "--"}
+	if dir != "" {
+		args = append([]string{"-C", dir}, args...)
+	}
+	out, err := r.run(args...)
+	if err != nil {
+		return 0, 0, err
+	}
+	fields := strings.Fields(out)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rev-list output %q", out)
+	}
+	ahead, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	behind, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	return ahead, behind, nil
+}
+
+func (r *Repo) reopen() (*gogit.Repository, error) {
+	if r.IsBare() {
+		return gogit.PlainOpen(r.root)
+	}
+	return gogit.PlainOpenWithOptions(r.root, openOptions)
+}
@@ -4,26 +4,29 @@ import (
 	"fmt"
 	"os/exec"
 	"sort"
-	"strconv"
 	"strings"
 
-	gogit "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 )
 
 func (r *Repo) DefaultBranch() string {
-	// Try refs/remotes/origin/HEAD symbolic ref
-	ref, err := r.repo.Reference(plumbing.NewRemoteReferenceName("origin", "HEAD"), false)
+	remote := r.CanonicalRemote()
+	if remote == "" {
+		return ""
+	}
+
+	// Try refs/remotes/<remote>/HEAD symbolic ref
+	ref, err := r.repo.Reference(plumbing.NewRemoteReferenceName(remote, "HEAD"), false)
 	if err == nil {
 		target := ref.Target()
 		if target.IsRemote() {
-			return strings.TrimPrefix(target.Short(), "origin/")
+			return strings.TrimPrefix(target.Short(), remote+"/")
 		}
 	}
 
-	// Fall back to checking origin/main then origin/master
+	// Fall back to checking <remote>/main then <remote>/master
 	for _, name := range []string{"main", "master"} {
-		_, err := r.repo.Reference(plumbing.NewRemoteReferenceName("origin", name), true)
+		_, err := r.repo.Reference(plumbing.NewRemoteReferenceName(remote, name), true)
 		if err == nil {
 			return name
 		}
@@ -31,46 +34,62 @@ func (r *Repo) DefaultBranch() string {
 	return ""
 }
 
+// DefaultBranchRef returns the remote-tracking ref of the default branch on
+// the canonical remote, e.g. "origin/main", or "" when there is none.
+func (r *Repo) DefaultBranchRef() string {
+	name := r.DefaultBranch()
+	if name == "" {
+		return ""
+	}
+	return r.CanonicalRemote() + "/" + name
+}
+
+// AheadBehind counts the commits only reachable from refA and only reachable
+// from refB. It returns zeros when either ref can't be resolved.
 func (r *Repo) AheadBehind(refA, refB string) (int, int) {
-	cmd := exec.Command("git", "rev-list", "--left-right", "--count", refA+"..."+refB)
-	cmd.Dir = r.path
-	out, err := cmd.Output()
-	if err != nil {
+	a, okA := r.resolveCommit(refA)
+	b, okB := r.resolveCommit(refB)
+	if !okA || !okB {
 		return 0, 0
 	}
-	parts := strings.Fields(strings.TrimSpace(string(out)))
-	if len(parts) != 2 {
+	w := newGraphWalker(r, r.repo, newNodeCache())
+	defer w.Close()
+	ahead, behind, err := w.count(a, b)
+	if err != nil {
 		return 0, 0
 	}
-	ahead, _ := strconv.Atoi(parts[0])
-	behind, _ := strconv.Atoi(parts[1])
 	return ahead, behind
 }
 
+// resolveCommit resolves any revision git understands. go-git's resolver
+// takes hex-looking branch names such as "f5" for abbreviated hashes.
+func (r *Repo) resolveCommit(rev string) (plumbing.Hash, bool) {
+	out, err := r.run("rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil || !plumbing.IsHash(out) {
+		return plumbing.ZeroHash, false
+	}
+	return plumbing.NewHash(out), true
+}
+
+// ListBranches returns local branches without their ahead/behind counts,
+// which StreamAheadBehind fills in.
 func (r *Repo) ListBranches() ([]Branch, error) {
-	head, err := r.repo.Head()
-	if err != nil {
-		return nil, fmt.Errorf("cannot read HEAD: %w", err)
+	// A fresh repository has no branches yet, and a detached HEAD has no
+	// current one
+	head := r.Head()
+	if head.Unborn {
+		return nil, nil
 	}
-	currentBranch := head.Name().Short()
+	currentBranch := r.CurrentBranch()
 
 	refs, err := r.repo.References()
 	if err != nil {
 		return nil, err
 	}
 
-	// Build set of remote branch names
-	remoteSet := make(map[string]bool)
 	var localRefs []*plumbing.Reference
 	err = refs.ForEach(func(ref *plumbing.Reference) error {
-		name := ref.Name()
-		if name.IsRemote() {
-			short := strings.TrimPrefix(name.Short(), "origin/")
-			if short != "HEAD" {
-				remoteSet[short] = true
-			}
-		}
-		if name.IsBranch() {
+		if ref.Name().IsBranch() {
 			localRefs = append(localRefs, ref)
 		}
 		return nil
@@ -80,6 +99,7 @@ func (r *Repo) ListBranches() ([]Branch, error) {
 	}
 
 	defaultBranch := r.DefaultBranch()
+	defaultRef := r.DefaultBranchRef()
 
 	var branches []Branch
 	for _, ref := range localRefs {
@@ -91,6 +111,7 @@ func (r *Repo) ListBranches() ([]Branch, error) {
 			continue
 		}
 
+		upstream := r.UpstreamRef(name)
 		b := Branch{
 			Name:          name,
 			Hash:          hash.String()[:7],
@@ -98,19 +119,13 @@ func (r *Repo) ListBranches() ([]Branch, error) {
 			Author:        commit.Author.Name,
 			When:          commit.Author.When,
 			IsCurrent:     name == currentBranch,
-			HasRemote:     remoteSet[name],
+			HasRemote:     upstream != "",
+			Upstream:      upstream,
 			DefaultBranch: defaultBranch,
+			DefaultRef:    defaultRef,
 			IsDefault:     name == defaultBranch,
 		}
 
-		if b.HasRemote {
-			b.RemoteAhead, b.RemoteBehind = r.AheadBehind(name, "origin/"+name)
-		}
-
-		if defaultBranch != "" && !b.IsDefault {
-			b.DefaultAhead, b.DefaultBehind = r.AheadBehind(name, "origin/"+defaultBranch)
-		}
-
 		branches = append(branches, b)
 	}
 
@@ -133,18 +148,12 @@ func (r *Repo) CreateBranch(name string) error {
 		return fmt.Errorf("cannot read HEAD: %w", err)
 	}
 
-	ref := plumbing.NewHashReference(plumbing.NewBranchReferenceName(name), head.Hash())
-	if err := r.repo.Storer.SetReference(ref); err != nil {
+	// git checkout, unlike go-git, records the switch in the reflog that
+	// undo reads
+	if _, err := r.run("checkout", "-b", name, head.Hash().String()); err != nil {
 		return fmt.Errorf("failed to create branch: %w", err)
 	}
-
-	w, err := r.repo.Worktree()
-	if err != nil {
-		return err
-	}
-	return w.Checkout(&gogit.CheckoutOptions{
-		Branch: plumbing.NewBranchReferenceName(name),
-	})
+	return nil
 }
 
 func (r *Repo) RenameBranch(oldName, newName string) error {
@@ -156,16 +165,23 @@ func (r *Repo) RenameBranch(oldName, newName string) error {
 	return nil
 }
 
+// RenameRemoteBranch renames the remote copy of a branch that was already
+// renamed locally, on the remote the branch tracks.
 func (r *Repo) RenameRemoteBranch(oldName, newName string) error {
+	remote, merge := r.Upstream(newName)
+	if remote == "" {
+		remote, merge = r.PushRemote(newName), oldName
+	}
+
 	// Delete old remote branch
-	cmd := exec.Command("git", "push", "origin", "--delete", oldName)
+	cmd := exec.Command("git", "push", remote, "--delete", merge)
 	cmd.Dir = r.path
 	if out, err := cmd.CombinedOutput(); err != nil {
 		return fmt.Errorf("delete remote: %s", strings.TrimSpace(string(out)))
 	}
 
 	// Push new branch name
-	cmd = exec.Command("git", "push", "-u", "origin", newName)
+	cmd = exec.Command("git", "push", "-u", remote, newName)
 	cmd.Dir = r.path
 	if out, err := cmd.CombinedOutput(); err != nil {
 		return fmt.Errorf("push new name: %s", strings.TrimSpace(string(out)))
@@ -173,51 +189,52 @@ func (r *Repo) RenameRemoteBranch(oldName, newName string) error {
 	return nil
 }
 
-func (r *Repo) PushBranch(branch string) error {
-	cmd := exec.Command("git", "push", "-u", "origin", branch)
+// PushBranch pushes branch to its push remote and returns that remote.
+func (r *Repo) PushBranch(branch string) (string, error) {
+	remote := r.PushRemote(branch)
+	cmd := exec.Command("git", "push", "-u", remote, branch)
 	cmd.Dir = r.path
 	if out, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("%s", strings.TrimSpace(string(out)))
+		return remote, fmt.Errorf("%s", strings.TrimSpace(string(out)))
 	}
-	return nil
+	return remote, nil
 }
 
+// CurrentBranch returns the checked-out branch, including one without
+// commits yet, or "" when HEAD is detached.
 func (r *Repo) CurrentBranch() string {
-	head, err := r.repo.Head()
-	if err != nil {
+	head := r.Head()
+	if head.Detached {
 		return ""
 	}
-	return head.Name().Short()
+	return head.Branch
 }
 
+// Checkout switches to branchName, creating it from the first remote that
+// has it when there is no local branch. It runs git checkout so the switch
+// is recorded in the reflog.
 func (r *Repo) Checkout(branchName string) error {
-	w, err := r.repo.Worktree()
-	if err != nil {
-		return err
+	// Refuse any local changes, not only conflicting ones, so the caller can
+	// offer to stash them
+	if files := r.dirtyFiles(); len(files) > 0 {
+		return &DirtyWorktreeError{Files: files}
 	}
 
 	localRef := plumbing.NewBranchReferenceName(branchName)
-	_, err = r.repo.Reference(localRef, false)
-	if err == nil {
-		return w.Checkout(&gogit.CheckoutOptions{
-			Branch: localRef,
-		})
-	}
-
-	remoteRef := plumbing.NewRemoteReferenceName("origin", branchName)
-	ref, err := r.repo.Reference(remoteRef, true)
-	if err != nil {
-		return fmt.Errorf("branch %q not found locally or in origin", branchName)
-	}
-
-	err = r.repo.Storer.SetReference(plumbing.NewHashReference(localRef, ref.Hash()))
-	if err != nil {
+	if _, err := r.repo.Reference(localRef, false); err == nil {
+		_, err := r.run("checkout", branchName, "--")
 		return err
 	}
 
-	return w.Checkout(&gogit.CheckoutOptions{
-		Branch: localRef,
-	})
+	// Create the local branch from the first remote that has it
+	for _, remote := range r.Remotes() {
+		remoteRef := plumbing.NewRemoteReferenceName(remote, branchName)
+		if _, err := r.repo.Reference(remoteRef, true); err == nil {
+			_, err := r.run("checkout", "-b", branchName, remoteRef.Short(), "--")
+			return err
+		}
+	}
+	return fmt.Errorf("branch %q not found locally or on any remote", branchName)
 }
 
 func firstLine(s string) string {
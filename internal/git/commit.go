@@ -0,0 +1,62 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+type CommitOptions struct {
+	Message string
+	Amend   bool
+	SignOff bool
+	Sign    bool
+}
+
+// CommitMessageFile returns the path git uses for commit messages, so an
+// external editor gets the usual filetype detection.
+func (r *Repo) CommitMessageFile() (string, error) {
+	dir, err := r.gitDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "COMMIT_EDITMSG"), nil
+}
+
+// CommitCmd writes the message to the commit message file and prepares the
+// git commit invocation. Signing is left to git's own gpg/ssh configuration;
+// callers that sign should run the command attached to the terminal so a
+// pinentry prompt can show.
+func (r *Repo) CommitCmd(opts CommitOptions) (*exec.Cmd, error) {
+	path, err := r.CommitMessageFile()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, []byte(opts.Message), 0o644); err != nil {
+		return nil, err
+	}
+
+	args := []string{"commit", "--cleanup=strip", "-F", path}
+	if opts.Amend {
+		args = append(args, "--amend")
+	}
+	if opts.SignOff {
+		args = append(args, "--signoff")
+	}
+	if opts.Sign {
+		args = append(args, "-S")
+	} else {
+		args = append(args, "--no-gpg-sign")
+	}
+	return r.command(args...), nil
+}
+
+func (r *Repo) LastCommitMessage() (string, error) {
+	return r.run("log", "-1", "--format=%B")
+}
+
+// SigningEnabled reports whether commit.gpgsign is set.
+func (r *Repo) SigningEnabled() bool {
+	out, err := r.run("config", "--bool", "commit.gpgsign")
+	return err == nil && out == "true"
+}
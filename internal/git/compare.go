@@ -0,0 +1,87 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const compareLimit = 500
+
+// Comparison describes how two refs have diverged since their merge base.
+type Comparison struct {
+	Base     string
+	Head     string
+	Ahead    int // commits on Head that are not on Base
+	Behind   int // commits on Base that are not on Head
+	HeadOnly []Commit
+	BaseOnly []Commit
+	Files    []FileStat
+}
+
+type FileStat struct {
+	Path    string
+	Added   int
+	Deleted int
+	Binary  bool
+}
+
+// Compare compares any two revisions: branches, remote branches, tags or
+// SHAs. Commit lists are newest first and capped at 500 per side.
+func (r *Repo) Compare(base, head string) (*Comparison, error) {
+	for _, rev := range []string{base, head} {
+		if _, err := r.run("rev-parse", "--verify", "--quiet", rev+"^{commit}"); err != nil {
+			return nil, fmt.Errorf("unknown revision %q", rev)
+		}
+	}
+
+	c := &Comparison{Base: base, Head: head}
+	// git counts, as it lists the commits below, so the two always agree
+	var err error
+	if c.Ahead, c.Behind, err = r.revListCount(head, base); err != nil {
+		return nil, fmt.Errorf("git rev-list: %w", err)
+	}
+	if c.HeadOnly, err = r.commitsOnly(head, base); err != nil {
+		return nil, err
+	}
+	if c.BaseOnly, err = r.commitsOnly(base, head); err != nil {
+		return nil, err
+	}
+
+	out, err := r.run("diff", "--numstat", "--no-renames", base+"..."+head, "--")
+	if err != nil {
+		return nil, fmt.Errorf("git diff: %w", err)
+	}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		f := FileStat{Path: fields[2], Binary: fields[0] == "-"}
+		f.Added, _ = strconv.Atoi(fields[0])
+		f.Deleted, _ = strconv.Atoi(fields[1])
+		c.Files = append(c.Files, f)
+	}
+	return c, nil
+}
+
+// CompareDiff returns the patch of everything head changed since it forked
+// from base.
+func (r *Repo) CompareDiff(base, head string) (string, error) {
+	return r.run("diff", base+"..."+head, "--")
+}
+
+// commitsOnly lists commits reachable from rev but not from other.
+func (r *Repo) commitsOnly(rev, other string) ([]Commit, error) {
+	out, err := r.run("log", "--format="+logFormat, "-n", strconv.Itoa(compareLimit), other+".."+rev, "--")
+	if err != nil {
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+	var commits []Commit
+	for _, line := range strings.Split(out, "\n") {
+		if _, c, ok := parseLogLine(line); ok {
+			commits = append(commits, c)
+		}
+	}
+	return commits, nil
+}
@@ -0,0 +1,61 @@
+package git
+
+import (
+	"errors"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// ErrBareRepository is returned by operations that need a working tree.
+var ErrBareRepository = errors.New("bare repository has no working tree")
+
+// HeadState describes what HEAD points at.
+type HeadState struct {
+	Branch   string // checked-out branch, also set when it has no commits yet
+	Hash     string // short hash, empty when unborn
+	Detached bool
+	Unborn   bool
+}
+
+func (r *Repo) Head() HeadState {
+	ref, err := r.repo.Storer.Reference(plumbing.HEAD)
+	if err != nil {
+		return HeadState{}
+	}
+
+	var state HeadState
+	if ref.Type() == plumbing.SymbolicReference {
+		state.Branch = ref.Target().Short()
+		resolved, err := r.repo.Reference(ref.Target(), true)
+		if err != nil {
+			state.Unborn = true
+			return state
+		}
+		state.Hash = resolved.Hash().String()[:7]
+		return state
+	}
+
+	state.Detached = true
+	state.Hash = ref.Hash().String()[:7]
+	return state
+}
+
+// NearestRef names the closest branch or tag to HEAD, e.g. "main~2", or
+// returns "" when there is none. It runs git name-rev, which can be slow on
+// large repositories, so call it off the UI loop.
+func (r *Repo) NearestRef() string {
+	if name, err := r.run("name-rev", "--name-only", "--no-undefined", "HEAD"); err == nil {
+		return name
+	}
+	if tag, err := r.run("describe", "--tags", "HEAD"); err == nil {
+		return tag
+	}
+	return ""
+}
+
+// IsBare reports whether the repository has no working tree.
+func (r *Repo) IsBare() bool {
+	_, err := r.repo.Worktree()
+	return errors.Is(err, gogit.ErrIsBareRepository)
+}
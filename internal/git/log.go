@@ -0,0 +1,79 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Commit struct {
+	Hash      string
+	ShortHash string
+	Author    string
+	TimeAgo   string
+	Refs      string
+	Subject   string
+}
+
+// LogLine is one row of `git log --graph`. Rows that only continue the graph
+// lanes between commits have a nil Commit.
+type LogLine struct {
+	Graph  string
+	Commit *Commit
+}
+
+const logFormat = "%x00%H%x00%h%x00%an%x00%ar%x00%D%x00%s"
+
+// Log walks history from rev, which may also be a range such as
+// "origin/main..feature", and returns at most limit commits with their
+// graph lanes.
+func (r *Repo) Log(rev string, limit int) ([]LogLine, error) {
+	out, err := r.run("log", "--graph", "--color=never", "--format="+logFormat, "-n", fmt.Sprintf("%d", limit), rev, "--")
+	if err != nil {
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+
+	var lines []LogLine
+	for _, line := range strings.Split(out, "\n") {
+		if line == "" {
+			continue
+		}
+		graph, commit, ok := parseLogLine(line)
+		if !ok {
+			lines = append(lines, LogLine{Graph: line})
+			continue
+		}
+		lines = append(lines, LogLine{Graph: graph, Commit: &commit})
+	}
+	return lines, nil
+}
+
+// CommitsBetween lists the non-merge commits reachable from head but not from
+// base, oldest first.
+func (r *Repo) CommitsBetween(base, head string) ([]Commit, error) {
+	out, err := r.run("log", "--reverse", "--no-merges", "--format="+logFormat, base+".."+head, "--")
+	if err != nil {
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+	var commits []Commit
+	for _, line := range strings.Split(out, "\n") {
+		if _, c, ok := parseLogLine(line); ok {
+			commits = append(commits, c)
+		}
+	}
+	return commits, nil
+}
+
+func parseLogLine(line string) (string, Commit, bool) {
+	parts := strings.Split(line, "\x00")
+	if len(parts) != 7 {
+		return "", Commit{}, false
+	}
+	return parts[0], Commit{
+		Hash:      parts[1],
+		ShortHash: parts[2],
+		Author:    parts[3],
+		TimeAgo:   parts[4],
+		Refs:      parts[5],
+		Subject:   parts[6],
+	}, true
+}
@@ -0,0 +1,205 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+type Operation int
+
+const (
+	OpNone Operation = iota
+	OpRebase
+	OpMerge
+	OpCherryPick
+)
+
+func (o Operation) String() string {
+	switch o {
+	case OpRebase:
+		return "rebase"
+	case OpMerge:
+		return "merge"
+	case OpCherryPick:
+		return "cherry-pick"
+	default:
+		return ""
+	}
+}
+
+// CanSkip reports whether the operation supports skipping the current commit.
+func (o Operation) CanSkip() bool {
+	return o == OpRebase || o == OpCherryPick
+}
+
+// InProgress detects a rebase, merge or cherry-pick that git stopped in the
+// middle of, usually because of conflicts.
+func (r *Repo) InProgress() Operation {
+	dir, err := r.gitDir()
+	if err != nil {
+		return OpNone
+	}
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(dir, name))
+		return err == nil
+	}
+	switch {
+	case exists("rebase-merge"), exists("rebase-apply"):
+		return OpRebase
+	case exists("CHERRY_PICK_HEAD"):
+		return OpCherryPick
+	case exists("MERGE_HEAD"):
+		return OpMerge
+	}
+	return OpNone
+}
+
+func (r *Repo) ConflictedFiles() ([]string, error) {
+	out, err := r.run("diff", "--name-only", "--diff-filter=U")
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, line := range strings.Split(out, "\n") {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// Rebase replays branch on top of onto. git checks out branch first, so this
+// works for any local branch, not only the current one.
+func (r *Repo) Rebase(branch, onto string) error {
+	_, err := r.run("rebase", onto, branch)
+	return err
+}
+
+// Merge merges ref into branch, checking branch out first if needed.
+func (r *Repo) Merge(branch, ref string) error {
+	if r.CurrentBranch() != branch {
+		if err := r.Checkout(branch); err != nil {
+			return err
+		}
+	}
+	_, err := r.run("merge", "--no-edit", ref)
+	return err
+}
+
+func (r *Repo) MarkResolved(path string) error {
+	_, err := r.run("add", "--", path)
+	return err
+}
+
+func (r *Repo) AbortOperation(op Operation) error {
+	if op == OpNone {
+		return fmt.Errorf("no operation in progress")
+	}
+	_, err := r.run(op.String(), "--abort")
+	return err
+}
+
+func (r *Repo) ContinueOperation(op Operation) error {
+	if op == OpNone {
+		return fmt.Errorf("no operation in progress")
+	}
+	// Keep git from opening an editor for the commit message
+	cmd := r.command(op.String(), "--continue")
+	cmd.Env = append(os.Environ(), "GIT_EDITOR=true")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (r *Repo) SkipOperation(op Operation) error {
+	if !op.CanSkip() {
+		return fmt.Errorf("cannot skip during %s", op)
+	}
+	_, err := r.run(op.String(), "--skip")
+	return err
+}
+
+// EditorCmd builds a command that opens path in the user's configured editor,
+// resolved the same way git does (GIT_EDITOR, core.editor, VISUAL, EDITOR).
+func (r *Repo) EditorCmd(path string) *exec.Cmd {
+	editor, err := r.run("var", "GIT_EDITOR")
+	if err != nil || editor == "" {
+		editor = "vi"
+	}
+	cmd := exec.Command("sh", "-c", editor+` "$@"`, editor, path)
+	cmd.Dir = r.root
+	return cmd
+}
+
+type RebaseAction string
+
+const (
+	RebasePick   RebaseAction = "pick"
+	RebaseReword RebaseAction = "reword"
+	RebaseEdit   RebaseAction = "edit"
+	RebaseSquash RebaseAction = "squash"
+	RebaseFixup  RebaseAction = "fixup"
+	RebaseDrop   RebaseAction = "drop"
+)
+
+// NeedsEditor reports whether git will ask for a commit message.
+func (a RebaseAction) NeedsEditor() bool {
+	return a == RebaseReword || a == RebaseSquash
+}
+
+type RebaseStep struct {
+	Action RebaseAction
+	Commit Commit
+}
+
+func (r *Repo) MergeBase(a, b string) (string, error) {
+	return r.run("merge-base", a, b)
+}
+
+// RebaseInteractiveCmd prepares `git rebase -i base` with the todo list
+// replaced by steps, using GIT_SEQUENCE_EDITOR to install it. Unless a step
+// needs a commit message, the command runs without an editor.
+func (r *Repo) RebaseInteractiveCmd(base string, steps []RebaseStep) (*exec.Cmd, error) {
+	var todo strings.Builder
+	needsEditor := false
+	for _, s := range steps {
+		fmt.Fprintf(&todo, "%s %s %s\n", s.Action, s.Commit.Hash, s.Commit.Subject)
+		needsEditor = needsEditor || s.Action.NeedsEditor()
+	}
+
+	dir, err := r.gitDir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, "HIT_REBASE_TODO")
+	if err := os.WriteFile(path, []byte(todo.String()), 0o644); err != nil {
+		return nil, err
+	}
+
+	cmd := r.command("rebase", "-i", base)
+	cmd.Env = append(os.Environ(), "GIT_SEQUENCE_EDITOR=cp "+shellQuote(path))
+	if !needsEditor {
+		cmd.Env = append(cmd.Env, "GIT_EDITOR=true")
+	}
+	return cmd, nil
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// CherryPick applies commits, given oldest first, onto the current branch.
+func (r *Repo) CherryPick(hashes ...string) error {
+	_, err := r.run(append([]string{"cherry-pick"}, hashes...)...)
+	return err
+}
+
+// CreateBranchFrom creates name at base and checks it out.
+func (r *Repo) CreateBranchFrom(name, base string) error {
+	_, err := r.run("checkout", "-b", name, base)
+	return err
+}
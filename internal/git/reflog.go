@@ -8,13 +8,23 @@ import (
 
 type ReflogEntry struct {
 	Selector string // HEAD@{0}
+	Hash     string // abbreviated commit hash
+	Commit   string // full commit hash, stable while Selector shifts
 	Action   string // checkout, commit, merge, etc.
 	Detail   string // human-friendly: "master → feature", "Fix bug", etc.
 	TimeAgo  string // "2 hours ago"
+	Subject  string // raw reflog subject
 }
 
 func (r *Repo) GetReflog(limit int) ([]ReflogEntry, error) {
-	cmd := exec.Command("git", "reflog", fmt.Sprintf("--format=%%gd|%%gs|%%ar"), "-n", fmt.Sprintf("%d", limit))
+	return r.reflog("HEAD", limit)
+}
+
+func (r *Repo) reflog(ref string, limit int) ([]ReflogEntry, error) {
+	if r.Head().Unborn {
+		return nil, nil
+	}
+	cmd := exec.Command("git", "reflog", "show", fmt.Sprintf("--format=%%gd|%%h|%%H|%%ar|%%gs"), "-n", fmt.Sprintf("%d", limit), ref, "--")
 	cmd.Dir = r.path
 	out, err := cmd.Output()
 	if err != nil {
@@ -27,26 +37,135 @@ func (r *Repo) GetReflog(limit int) ([]ReflogEntry, error) {
 		if line == "" {
 			continue
 		}
-		parts := strings.SplitN(line, "|", 3)
-		if len(parts) != 3 {
+		// The subject goes last since it may itself contain "|"
+		parts := strings.SplitN(line, "|", 5)
+		if len(parts) != 5 {
 			continue
 		}
 
 		selector := parts[0]
-		subject := parts[1]
-		timeAgo := parts[2]
+		hash := parts[1]
+		commit := parts[2]
+		timeAgo := parts[3]
+		subject := parts[4]
 
 		action, detail := parseReflogSubject(subject)
 		entries = append(entries, ReflogEntry{
 			Selector: selector,
+			Hash:     hash,
+			Commit:   commit,
 			Action:   action,
 			Detail:   detail,
 			TimeAgo:  timeAgo,
+			Subject:  subject,
 		})
 	}
 	return entries, nil
 }
 
+type ResetMode string
+
+const (
+	ResetSoft  ResetMode = "soft"
+	ResetMixed ResetMode = "mixed"
+	ResetHard  ResetMode = "hard"
+	ResetKeep  ResetMode = "keep"
+)
+
+// Reset moves the current branch (or detached HEAD) to ref.
+func (r *Repo) Reset(ref string, mode ResetMode) error {
+	_, err := r.run("reset", "--"+string(mode), ref)
+	return err
+}
+
+// CreateBranchAt creates name pointing at ref without checking it out.
+func (r *Repo) CreateBranchAt(name, ref string) error {
+	_, err := r.run("branch", name, ref)
+	return err
+}
+
+func (r *Repo) CheckoutDetached(ref string) error {
+	_, err := r.run("checkout", "--detach", ref)
+	return err
+}
+
+// UndoPlan describes how to revert the most recent reflog entry.
+type UndoPlan struct {
+	Entry       ReflogEntry
+	Description string
+	checkout    string
+	resetTo     string
+	mode        ResetMode
+}
+
+// PlanUndo works out how to revert the last operation recorded in the HEAD
+// reflog: checkouts switch back to the previous ref, commits are soft-reset
+// so their changes stay staged, and anything else that moved a branch
+// (rebase, reset, merge, cherry-pick, amend) resets it to its previous
+// position while keeping local changes. It refuses when HEAD is not where
+// that entry left it, since the entry then describes some other change.
+func (r *Repo) PlanUndo() (UndoPlan, error) {
+	if op := r.InProgress(); op != OpNone {
+		return UndoPlan{}, fmt.Errorf("%s in progress; finish or abort it first", op)
+	}
+	entries, err := r.GetReflog(1)
+	if err != nil {
+		return UndoPlan{}, err
+	}
+	if len(entries) == 0 {
+		return UndoPlan{}, fmt.Errorf("nothing to undo")
+	}
+	e := entries[0]
+	plan := UndoPlan{Entry: e}
+	if head, err := r.run("rev-parse", "HEAD"); err != nil || head != e.Commit {
+		return UndoPlan{}, fmt.Errorf("HEAD moved without a reflog entry; the last entry no longer describes it")
+	}
+
+	if e.Action == "checkout" {
+		from := strings.TrimPrefix(e.Subject, "checkout: moving from ")
+		idx := strings.Index(from, " to ")
+		if idx < 0 {
+			return UndoPlan{}, fmt.Errorf("cannot parse checkout: %s", e.Subject)
+		}
+		if branch := r.CurrentBranch(); branch != "" && branch != from[idx+4:] {
+			return UndoPlan{}, fmt.Errorf("HEAD moved without a reflog entry; the last entry no longer describes it")
+		}
+		plan.checkout = from[:idx]
+		plan.Description = "switch back to " + plan.checkout
+		return plan, nil
+	}
+
+	if strings.HasPrefix(e.Action, "rebase") && !strings.Contains(e.Action, "finish") {
+		return UndoPlan{}, fmt.Errorf("last reflog entry is part of an unfinished rebase")
+	}
+
+	target := "HEAD@{1}"
+	if branch := r.CurrentBranch(); branch != "" {
+		target = branch + "@{1}"
+	}
+	// Resolve the target now: the @{1} selector shifts with every entry
+	hash, err := r.run("rev-parse", "--verify", "--quiet", target+"^{commit}")
+	if err != nil {
+		return UndoPlan{}, fmt.Errorf("no earlier position to return to")
+	}
+
+	plan.resetTo = hash
+	plan.mode = ResetKeep
+	if strings.HasPrefix(e.Action, "commit") {
+		plan.mode = ResetSoft
+	}
+	plan.Description = fmt.Sprintf("reset --%s to %s (%s)", plan.mode, target, hash[:7])
+	return plan, nil
+}
+
+func (r *Repo) Undo(plan UndoPlan) error {
+	if plan.checkout != "" {
+		_, err := r.run("checkout", plan.checkout)
+		return err
+	}
+	return r.Reset(plan.resetTo, plan.mode)
+}
+
 func parseReflogSubject(subject string) (string, string) {
 	// Subject format: "action: detail"
 	colonIdx := strings.Index(subject, ": ")
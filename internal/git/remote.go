@@ -0,0 +1,106 @@
+package git
+
+import (
+	"sort"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// Remotes lists the configured remote names, origin and upstream first.
+func (r *Repo) Remotes() []string {
+	cfg, err := r.repo.Config()
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for name := range cfg.Remotes {
+		names = append(names, name)
+	}
+	rank := func(name string) int {
+		switch name {
+		case "origin":
+			return 0
+		case "upstream":
+			return 1
+		}
+		return 2
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if rank(names[i]) != rank(names[j]) {
+			return rank(names[i]) < rank(names[j])
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+func (r *Repo) hasRemote(name string) bool {
+	_, err := r.repo.Remote(name)
+	return err == nil
+}
+
+// CanonicalRemote is the remote that holds the main copy of the repository:
+// "upstream" when working from a fork, otherwise "origin", otherwise the
+// only remote there is.
+func (r *Repo) CanonicalRemote() string {
+	remotes := r.Remotes()
+	for _, name := range []string{"upstream", "origin"} {
+		if r.hasRemote(name) {
+			return name
+		}
+	}
+	if len(remotes) > 0 {
+		return remotes[0]
+	}
+	return ""
+}
+
+// Upstream returns the remote and remote branch name a local branch tracks,
+// from branch.<name>.remote and branch.<name>.merge.
+func (r *Repo) Upstream(branch string) (string, string) {
+	cfg, err := r.repo.Config()
+	if err != nil {
+		return "", ""
+	}
+	b, ok := cfg.Branches[branch]
+	if !ok || b.Remote == "" || b.Remote == "." {
+		return "", ""
+	}
+	merge := b.Merge.Short()
+	if merge == "" {
+		merge = branch
+	}
+	return b.Remote, merge
+}
+
+// UpstreamRef returns the remote-tracking ref of branch, such as
+// "origin/feature". Branches without tracking configuration fall back to a
+// same-named branch on origin. It returns "" when neither exists.
+func (r *Repo) UpstreamRef(branch string) string {
+	remote, merge := r.Upstream(branch)
+	if remote == "" {
+		remote, merge = "origin", branch
+	}
+	if _, err := r.repo.Reference(plumbing.NewRemoteReferenceName(remote, merge), true); err != nil {
+		return ""
+	}
+	return remote + "/" + merge
+}
+
+// PushRemote picks where to push branch: branch.<name>.pushRemote,
+// remote.pushDefault, the tracked remote, then origin.
+func (r *Repo) PushRemote(branch string) string {
+	if remote, err := r.run("config", "--get", "branch."+branch+".pushRemote"); err == nil && remote != "" {
+		return remote
+	}
+	if remote, err := r.run("config", "--get", "remote.pushDefault"); err == nil && remote != "" {
+		return remote
+	}
+	if remote, _ := r.Upstream(branch); remote != "" {
+		return remote
+	}
+	if r.hasRemote("origin") || len(r.Remotes()) == 0 {
+		return "origin"
+	}
+	return r.Remotes()[0]
+}
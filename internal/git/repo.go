@@ -2,27 +2,49 @@ package git
 
 import (
 	"fmt"
+	neturl "net/url"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 
 	gogit "github.com/go-git/go-git/v5"
-	"github.com/go-git/go-git/v5/plumbing"
 )
 
 type Repo struct {
 	repo *gogit.Repository
 	path string
+	root string
+}
+
+// openOptions also reads objects and refs from the main repository when path
+// is a linked worktree.
+var openOptions = &gogit.PlainOpenOptions{
+	DetectDotGit:          true,
+	EnableDotGitCommonDir: true,
 }
 
 func Open(path string) (*Repo, error) {
-	r, err := gogit.PlainOpenWithOptions(path, &gogit.PlainOpenOptions{
-		DetectDotGit: true,
-	})
+	root := path
+	r, err := gogit.PlainOpenWithOptions(path, openOptions)
 	if err != nil {
-		return nil, fmt.Errorf("not a git repository: %w", err)
+		// go-git only detects repositories through a .git entry; ask git
+		// where the repository is to also find bare ones
+		cmd := exec.Command("git", "rev-parse", "--absolute-git-dir")
+		cmd.Dir = path
+		out, gitErr := cmd.Output()
+		if gitErr != nil {
+			return nil, fmt.Errorf("not a git repository: %w", err)
+		}
+		root = strings.TrimSpace(string(out))
+		if r, err = gogit.PlainOpen(root); err != nil {
+			return nil, fmt.Errorf("not a git repository: %w", err)
+		}
 	}
-	return &Repo{repo: r, path: path}, nil
+	if w, err := r.Worktree(); err == nil {
+		root = w.Filesystem.Root()
+	}
+	return &Repo{repo: r, path: path, root: root}, nil
 }
 
 func OpenCwd() (*Repo, error) {
@@ -41,14 +63,50 @@ func (r *Repo) Path() string {
 	return abs
 }
 
+// Root is the top of the working tree, or the git directory of a bare
+// repository.
+func (r *Repo) Root() string {
+	return r.root
+}
+
+// command prepares a git invocation at the top of the working tree, so paths
+// reported by git can be passed straight back to it.
+func (r *Repo) command(args ...string) *exec.Cmd {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.root
+	return cmd
+}
+
+// run executes git with args in the repository and returns its trimmed
+// stdout. On failure the error carries git's combined output.
+func (r *Repo) run(args ...string) (string, error) {
+	cmd := r.command(args...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = strings.TrimSpace(string(out))
+		}
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("%s", msg)
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
+
+func (r *Repo) gitDir() (string, error) {
+	return r.run("rev-parse", "--absolute-git-dir")
+}
+
 func (r *Repo) HasUpstream(branch string) bool {
-	ref := plumbing.NewRemoteReferenceName("origin", branch)
-	_, err := r.repo.Reference(ref, true)
-	return err == nil
+	return r.UpstreamRef(branch) != ""
 }
 
-func (r *Repo) RemoteURL() string {
-	remote, err := r.repo.Remote("origin")
+func (r *Repo) RemoteURL(name string) string {
+	remote, err := r.repo.Remote(name)
 	if err != nil {
 		return ""
 	}
@@ -59,33 +117,69 @@ func (r *Repo) RemoteURL() string {
 	return urls[0]
 }
 
-func (r *Repo) OwnerRepo() (string, string, error) {
-	url := r.RemoteURL()
+// GitHubRepo identifies the GitHub repository behind the canonical remote,
+// so CI and PRs target the parent repository when working from a fork. The
+// host is github.com or a GitHub Enterprise Server host.
+func (r *Repo) GitHubRepo() (host, owner, name string, err error) {
+	url := r.RemoteURL(r.CanonicalRemote())
 	if url == "" {
-		return "", "", fmt.Errorf("no origin remote found")
+		return "", "", "", fmt.Errorf("no remote found")
 	}
+	return ParseRemoteURL(url)
+}
 
-	url = strings.TrimSuffix(url, ".git")
+func (r *Repo) OwnerRepo() (string, string, error) {
+	_, owner, name, err := r.GitHubRepo()
+	return owner, name, err
+}
 
-	if strings.HasPrefix(url, "git@") {
-		parts := strings.SplitN(url, ":", 2)
-		if len(parts) != 2 {
-			return "", "", fmt.Errorf("cannot parse remote URL: %s", url)
+// notGitHub lists well-known hosts that are certainly not GitHub; any other
+// host may be a GitHub Enterprise Server.
+var notGitHub = map[string]bool{
+	"gitlab.com":    true,
+	"bitbucket.org": true,
+	"codeberg.org":  true,
+}
+
+// ParseRemoteURL splits a remote URL into host, owner and repository name.
+// It accepts scp-like (git@host:owner/repo), ssh:// and http(s):// URLs. The
+// port of an ssh URL is dropped, since the API is served over HTTPS, while a
+// non-default HTTPS port is kept as part of the host.
+func ParseRemoteURL(raw string) (host, owner, name string, err error) {
+	var path string
+	if u, perr := neturl.Parse(raw); perr == nil && u.Scheme != "" && u.Host != "" {
+		switch u.Scheme {
+		case "https":
+			host = strings.TrimSuffix(u.Host, ":443")
+		case "http":
+			host = strings.TrimSuffix(u.Host, ":80")
+		default:
+			host = u.Hostname()
+		}
+		path = u.Path
+	} else {
+		hostPart, p, ok := strings.Cut(raw, ":")
+		if !ok || hostPart == "" || strings.Contains(hostPart, "/") {
+			return "", "", "", fmt.Errorf("cannot parse remote URL: %s", raw)
 		}
-		segments := strings.Split(parts[1], "/")
-		if len(segments) < 2 {
-			return "", "", fmt.Errorf("cannot parse remote URL: %s", url)
+		if at := strings.LastIndex(hostPart, "@"); at >= 0 {
+			hostPart = hostPart[at+1:]
 		}
-		return segments[len(segments)-2], segments[len(segments)-1], nil
+		host, path = hostPart, p
 	}
 
-	if strings.Contains(url, "github.com") {
-		parts := strings.Split(url, "/")
-		if len(parts) < 2 {
-			return "", "", fmt.Errorf("cannot parse remote URL: %s", url)
-		}
-		return parts[len(parts)-2], parts[len(parts)-1], nil
+	host = strings.ToLower(host)
+	switch host {
+	case "ssh.github.com", "www.github.com":
+		host = "github.com"
+	}
+	if notGitHub[host] {
+		return "", "", "", fmt.Errorf("not a GitHub remote: %s", raw)
 	}
 
-	return "", "", fmt.Errorf("not a GitHub remote: %s", url)
+	segments := strings.Split(strings.TrimSuffix(strings.Trim(path, "/"), ".git"), "/")
+	if len(segments) < 2 || segments[len(segments)-2] == "" || segments[len(segments)-1] == "" {
+		return "", "", "", fmt.Errorf("cannot parse remote URL: %s", raw)
+	}
+	return host, segments[len(segments)-2], segments[len(segments)-1], nil
 }
@@ -0,0 +1,84 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Stash struct {
+	Ref     string // stash@{0}
+	Message string
+	Branch  string
+	TimeAgo string
+	Files   int
+}
+
+func (r *Repo) ListStashes() ([]Stash, error) {
+	out, err := r.run("stash", "list", "--format=%gd%x00%gs%x00%cr")
+	if err != nil {
+		return nil, fmt.Errorf("git stash list: %w", err)
+	}
+
+	var stashes []Stash
+	for _, line := range strings.Split(out, "\n") {
+		parts := strings.SplitN(line, "\x00", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		branch, message := parseStashSubject(parts[1])
+		s := Stash{
+			Ref:     parts[0],
+			Message: message,
+			Branch:  branch,
+			TimeAgo: parts[2],
+		}
+		if files, err := r.run("diff", "--name-only", s.Ref+"^1", s.Ref); err == nil && files != "" {
+			s.Files = len(strings.Split(files, "\n"))
+		}
+		stashes = append(stashes, s)
+	}
+	return stashes, nil
+}
+
+// parseStashSubject splits "WIP on main: abc1234 subject" or
+// "On main: message" into branch and message.
+func parseStashSubject(subject string) (string, string) {
+	rest := strings.TrimPrefix(strings.TrimPrefix(subject, "WIP "), "On ")
+	rest = strings.TrimPrefix(rest, "on ")
+	idx := strings.Index(rest, ": ")
+	if idx < 0 {
+		return "", subject
+	}
+	return rest[:idx], rest[idx+2:]
+}
+
+func (r *Repo) StashPush(message string, includeUntracked bool) error {
+	args := []string{"stash", "push"}
+	if includeUntracked {
+		args = append(args, "--include-untracked")
+	}
+	if message != "" {
+		args = append(args, "-m", message)
+	}
+	_, err := r.run(args...)
+	return err
+}
+
+func (r *Repo) StashApply(ref string) error {
+	_, err := r.run("stash", "apply", ref)
+	return err
+}
+
+func (r *Repo) StashPop(ref string) error {
+	_, err := r.run("stash", "pop", ref)
+	return err
+}
+
+func (r *Repo) StashDrop(ref string) error {
+	_, err := r.run("stash", "drop", ref)
+	return err
+}
+
+func (r *Repo) StashDiff(ref string) (string, error) {
+	return r.run("stash", "show", "-p", "--include-untracked", ref)
+}
@@ -0,0 +1,268 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type Section int
+
+const (
+	SectionConflicted Section = iota
+	SectionStaged
+	SectionUnstaged
+	SectionUntracked
+)
+
+func (s Section) String() string {
+	switch s {
+	case SectionConflicted:
+		return "Conflicted"
+	case SectionStaged:
+		return "Staged"
+	case SectionUnstaged:
+		return "Unstaged"
+	default:
+		return "Untracked"
+	}
+}
+
+type StatusEntry struct {
+	Path     string
+	OrigPath string // source path of a rename or copy
+	Section  Section
+	Code     byte // porcelain status letter: M, A, D, R, C, T, U or ?
+}
+
+// DirtyWorktreeError is returned when an operation would overwrite
+// uncommitted changes.
+type DirtyWorktreeError struct {
+	Files []string
+}
+
+func (e *DirtyWorktreeError) Error() string {
+	shown := e.Files
+	if len(shown) > 3 {
+		shown = shown[:3]
+	}
+	msg := fmt.Sprintf("uncommitted changes in %d file(s): %s", len(e.Files), strings.Join(shown, ", "))
+	if len(e.Files) > len(shown) {
+		msg += ", ..."
+	}
+	return msg
+}
+
+// Status lists the working tree changes grouped into sections. A file with
+// both staged and unstaged changes appears in both sections.
+func (r *Repo) Status() ([]StatusEntry, error) {
+	if r.IsBare() {
+		return nil, ErrBareRepository
+	}
+	out, err := r.run("status", "--porcelain=v2", "-z", "--untracked-files=all")
+	if err != nil {
+		return nil, err
+	}
+	return parseStatus(out), nil
+}
+
+func parseStatus(out string) []StatusEntry {
+	var entries []StatusEntry
+	fields := strings.Split(out, "\x00")
+	for i := 0; i < len(fields); i++ {
+		line := fields[i]
+		if line == "" {
+			continue
+		}
+		switch line[0] {
+		case '1', '2':
+			// 1 XY sub mH mI mW hH hI path
+			// 2 XY sub mH mI mW hH hI Xscore path, followed by origPath
+			n := 9
+			if line[0] == '2' {
+				n = 10
+			}
+			parts := strings.SplitN(line, " ", n)
+			if len(parts) != n {
+				continue
+			}
+			e := StatusEntry{Path: parts[n-1]}
+			if line[0] == '2' && i+1 < len(fields) {
+				i++
+				e.OrigPath = fields[i]
+			}
+			x, y := parts[1][0], parts[1][1]
+			if x != '.' {
+				staged := e
+				staged.Section = SectionStaged
+				staged.Code = x
+				entries = append(entries, staged)
+			}
+			if y != '.' {
+				unstaged := e
+				unstaged.Section = SectionUnstaged
+				unstaged.Code = y
+				entries = append(entries, unstaged)
+			}
+		case 'u':
+			// u XY sub m1 m2 m3 mW h1 h2 h3 path
+			parts := strings.SplitN(line, " ", 11)
+			if len(parts) != 11 {
+				continue
+			}
+			entries = append(entries, StatusEntry{Path: parts[10], Section: SectionConflicted, Code: 'U'})
+		case '?':
+			entries = append(entries, StatusEntry{Path: line[2:], Section: SectionUntracked, Code: '?'})
+		}
+	}
+	return entries
+}
+
+// dirtyFiles lists tracked files with unstaged or conflicting changes, which
+// would block switching branches.
+func (r *Repo) dirtyFiles() []string {
+	entries, err := r.Status()
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, e := range entries {
+		if e.Section == SectionUnstaged || e.Section == SectionConflicted {
+			files = append(files, e.Path)
+		}
+	}
+	return files
+}
+
+func (r *Repo) StageFile(path string) error {
+	_, err := r.run("add", "-A", "--", path)
+	return err
+}
+
+func (r *Repo) StageAll() error {
+	_, err := r.run("add", "-A")
+	return err
+}
+
+func (r *Repo) UnstageFile(path string) error {
+	_, err := r.run("reset", "-q", "--", path)
+	return err
+}
+
+// DiscardFile drops the changes of e. Untracked files are deleted; staged
+// entries are restored from HEAD in both the index and the working tree.
+func (r *Repo) DiscardFile(e StatusEntry) error {
+	var err error
+	switch e.Section {
+	case SectionUntracked:
+		_, err = r.run("clean", "-f", "--", e.Path)
+	case SectionStaged:
+		paths := []string{e.Path}
+		if e.OrigPath != "" {
+			paths = append(paths, e.OrigPath)
+		}
+		if e.Code == 'A' {
+			_, err = r.run(append([]string{"rm", "-f", "--"}, paths...)...)
+		} else {
+			_, err = r.run(append([]string{"checkout", "HEAD", "--"}, paths...)...)
+		}
+	default:
+		_, err = r.run("checkout", "--", e.Path)
+	}
+	return err
+}
+
+// Diff returns the patch for e: the index against HEAD for staged entries,
+// the working tree against the index otherwise.
+func (r *Repo) Diff(e StatusEntry) (string, error) {
+	switch e.Section {
+	case SectionStaged:
+		return r.run("diff", "--cached", "--", e.Path)
+	case SectionUntracked:
+		data, err := os.ReadFile(filepath.Join(r.root, e.Path))
+		if err != nil {
+			return "", err
+		}
+		return newFilePatch(e.Path, string(data)), nil
+	default:
+		return r.run("diff", "--", e.Path)
+	}
+}
+
+func newFilePatch(path, content string) string {
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	var b strings.Builder
+	fmt.Fprintf(&b, "diff --git a/%s b/%s\nnew file\n--- /dev/null\n+++ b/%s\n", path, path, path)
+	fmt.Fprintf(&b, "@@ -0,0 +1,%d @@\n", len(lines))
+	for _, l := range lines {
+		b.WriteString("+" + l + "\n")
+	}
+	return b.String()
+}
+
+// Hunk is one "@@" section of a single-file patch.
+type Hunk struct {
+	Header string
+	Lines  []string
+}
+
+// Patch is a single-file diff split into its file header and hunks.
+type Patch struct {
+	Header []string
+	Hunks  []Hunk
+}
+
+func ParsePatch(diff string) Patch {
+	var p Patch
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "@@") {
+			p.Hunks = append(p.Hunks, Hunk{Header: line})
+			continue
+		}
+		if len(p.Hunks) == 0 {
+			p.Header = append(p.Header, line)
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		h := &p.Hunks[len(p.Hunks)-1]
+		h.Lines = append(h.Lines, line)
+	}
+	return p
+}
+
+// HunkPatch renders a patch containing only hunk i, suitable for git apply.
+func (p Patch) HunkPatch(i int) string {
+	var b strings.Builder
+	for _, l := range p.Header {
+		b.WriteString(l + "\n")
+	}
+	b.WriteString(p.Hunks[i].Header + "\n")
+	for _, l := range p.Hunks[i].Lines {
+		b.WriteString(l + "\n")
+	}
+	return b.String()
+}
+
+func (r *Repo) StageHunk(p Patch, i int) error {
+	return r.apply(p.HunkPatch(i), "--cached")
+}
+
+func (r *Repo) UnstageHunk(p Patch, i int) error {
+	return r.apply(p.HunkPatch(i), "--cached", "--reverse")
+}
+
+func (r *Repo) DiscardHunk(p Patch, i int) error {
+	return r.apply(p.HunkPatch(i), "--reverse")
+}
+
+func (r *Repo) apply(patch string, args ...string) error {
+	cmd := r.command(append([]string{"apply", "--whitespace=nowarn"}, args...)...)
+	cmd.Stdin = strings.NewReader(patch)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type SubmoduleState int
+
+const (
+	SubmoduleOK SubmoduleState = iota
+	SubmoduleUninitialized
+	SubmoduleOutOfDate // checked-out commit differs from the one the superproject records
+	SubmoduleConflict
+)
+
+type Submodule struct {
+	Path     string
+	Recorded string // commit recorded in the superproject
+	Current  string // commit checked out in the submodule
+	State    SubmoduleState
+	Dirty    bool
+	Ahead    int // commits checked out beyond the recorded one
+	Behind   int // recorded commits not checked out
+}
+
+// NeedsAttention reports whether the submodule isn't checked out at the
+// recorded commit or has local changes.
+func (s Submodule) NeedsAttention() bool {
+	return s.State != SubmoduleOK || s.Dirty
+}
+
+// HasSubmodules is a cheap check for a .gitmodules file.
+func (r *Repo) HasSubmodules() bool {
+	_, err := os.Stat(filepath.Join(r.root, ".gitmodules"))
+	return err == nil
+}
+
+// Submodules parses `git submodule status --recursive` and inspects each
+// initialized submodule for local changes and drift from the recorded
+// commit.
+func (r *Repo) Submodules() ([]Submodule, error) {
+	if !r.HasSubmodules() {
+		return nil, nil
+	}
+	out, err := r.run("submodule", "status", "--recursive")
+	if err != nil {
+		return nil, err
+	}
+
+	var subs []Submodule
+	for _, line := range strings.Split(out, "\n") {
+		if len(line) < 42 {
+			continue
+		}
+		fields := strings.Fields(line[1:])
+		if len(fields) < 2 {
+			continue
+		}
+		s := Submodule{Path: fields[1], Current: fields[0][:7]}
+		switch line[0] {
+		case '-':
+			s.State = SubmoduleUninitialized
+			s.Current = ""
+		case '+':
+			s.State = SubmoduleOutOfDate
+		case 'U':
+			s.State = SubmoduleConflict
+		}
+
+		s.Recorded = r.recordedCommit(s.Path, subs)
+
+		if s.State != SubmoduleUninitialized {
+			dir := filepath.Join(r.root, s.Path)
+			status, err := r.run("-C", dir, "status", "--porcelain")
+			s.Dirty = err == nil && status != ""
+			if s.State == SubmoduleOutOfDate && s.Recorded != "" {
+				s.Ahead, s.Behind, _ = r.revListCountIn(dir, "HEAD", s.Recorded)
+			}
+		}
+		subs = append(subs, s)
+	}
+	return subs, nil
+}
+
+// recordedCommit is the commit recorded for the submodule at path by the
+// repository that contains it: the closest of parents whose path encloses
+// it, or the superproject for a top-level submodule. It is empty when the
+// entry cannot be read.
+func (r *Repo) recordedCommit(path string, parents []Submodule) string {
+	args := []string{"ls-tree", "HEAD", "--", path}
+	parent := ""
+	for _, p := range parents {
+		if strings.HasPrefix(path, p.Path+"/") && len(p.Path) > len(parent) {
+			parent = p.Path
+		}
+	}
+	if parent != "" {
+		args = []string{"-C", filepath.Join(r.root, parent), "ls-tree", "HEAD", "--", strings.TrimPrefix(path, parent+"/")}
+	}
+	out, err := r.run(args...)
+	if parts := strings.Fields(out); err == nil && len(parts) >= 3 && len(parts[2]) >= 7 {
+		return parts[2][:7]
+	}
+	return ""
+}
+
+// UpdateSubmodule initializes path if needed and checks out the recorded
+// commit, recursively. An empty path updates every submodule.
+func (r *Repo) UpdateSubmodule(path string) error {
+	args := []string{"submodule", "update", "--init", "--recursive"}
+	if path != "" {
+		args = append(args, "--", path)
+	}
+	_, err := r.run(args...)
+	return err
+}
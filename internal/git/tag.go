@@ -0,0 +1,83 @@
+package git
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Tag struct {
+	Name      string
+	Hash      string // commit the tag points to
+	Subject   string // tag message for annotated tags, commit subject otherwise
+	Annotated bool
+	When      time.Time
+}
+
+// ListTags returns local tags, newest first.
+func (r *Repo) ListTags() ([]Tag, error) {
+	out, err := r.run("for-each-ref", "refs/tags", "--sort=-creatordate",
+		"--format=%(refname:short)%00%(objecttype)%00%(*objectname:short)%00%(objectname:short)%00%(creatordate:unix)%00%(contents:subject)")
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+
+	var tags []Tag
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.SplitN(line, "\x00", 6)
+		if len(fields) < 6 {
+			continue
+		}
+		t := Tag{Name: fields[0], Annotated: fields[1] == "tag", Hash: fields[3], Subject: fields[5]}
+		if t.Annotated && fields[2] != "" {
+			t.Hash = fields[2]
+		}
+		if secs, err := strconv.ParseInt(fields[4], 10, 64); err == nil {
+			t.When = time.Unix(secs, 0)
+		}
+		tags = append(tags, t)
+	}
+	return tags, nil
+}
+
+// RemoteTags lists the tag names on remote. It talks to the remote.
+func (r *Repo) RemoteTags(remote string) (map[string]bool, error) {
+	out, err := r.run("ls-remote", "--tags", "--refs", remote)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool)
+	for _, line := range strings.Split(out, "\n") {
+		_, ref, ok := strings.Cut(line, "\t")
+		if ok {
+			names[strings.TrimPrefix(ref, "refs/tags/")] = true
+		}
+	}
+	return names, nil
+}
+
+// CreateTag creates an annotated tag name on rev.
+func (r *Repo) CreateTag(name, rev, message string) error {
+	_, err := r.run("tag", "-a", name, "-m", message, rev)
+	return err
+}
+
+// PushTag pushes a tag to the canonical remote, where releases are made.
+func (r *Repo) PushTag(name string) (string, error) {
+	remote := r.CanonicalRemote()
+	_, err := r.run("push", remote, "refs/tags/"+name)
+	return remote, err
+}
+
+// PreviousTag returns the closest tag reachable from the parent of tag, or ""
+// for the first tag in history.
+func (r *Repo) PreviousTag(tag string) string {
+	prev, err := r.run("describe", "--tags", "--abbrev=0", tag+"^")
+	if err != nil {
+		return ""
+	}
+	return prev
+}
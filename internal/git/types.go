@@ -11,10 +11,12 @@ type Branch struct {
 	IsCurrent     bool
 	IsRemote      bool
 	HasRemote     bool
+	Upstream      string // remote-tracking ref, e.g. "origin/feature"
 	RemoteAhead   int
 	RemoteBehind  int
 	DefaultAhead  int
 	DefaultBehind int
 	DefaultBranch string
+	DefaultRef    string // remote-tracking ref of the default branch, e.g. "upstream/main"
 	IsDefault     bool
 }
@@ -0,0 +1,149 @@
+package git
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+type Worktree struct {
+	Path     string
+	Head     string
+	Branch   string // empty when detached or bare
+	Bare     bool
+	Detached bool
+	Locked   bool
+	Prunable bool
+	Current  bool
+	Dirty    bool
+}
+
+// ListWorktrees parses `git worktree list --porcelain`. The main worktree
+// comes first.
+func (r *Repo) ListWorktrees() ([]Worktree, error) {
+	out, err := r.run("worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, err
+	}
+
+	current := canonicalPath(r.root)
+	var trees []Worktree
+	for _, block := range strings.Split(out, "\n\n") {
+		var wt Worktree
+		for _, line := range strings.Split(strings.TrimSpace(block), "\n") {
+			key, value, _ := strings.Cut(line, " ")
+			switch key {
+			case "worktree":
+				wt.Path = value
+			case "HEAD":
+				if len(value) >= 7 {
+					wt.Head = value[:7]
+				}
+			case "branch":
+				wt.Branch = strings.TrimPrefix(value, "refs/heads/")
+			case "bare":
+				wt.Bare = true
+			case "detached":
+				wt.Detached = true
+			case "locked":
+				wt.Locked = true
+			case "prunable":
+				wt.Prunable = true
+			}
+		}
+		if wt.Path == "" {
+			continue
+		}
+		wt.Current = canonicalPath(wt.Path) == current
+		if !wt.Bare && !wt.Prunable {
+			status, err := r.run("-C", wt.Path, "status", "--porcelain")
+			wt.Dirty = err == nil && status != ""
+		}
+		trees = append(trees, wt)
+	}
+	return trees, nil
+}
+
+func canonicalPath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return filepath.Clean(path)
+}
+
+var unsafePathChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// SiblingWorktreePath suggests a directory next to the main worktree for
+// name, e.g. ../hit-feature-x.
+func (r *Repo) SiblingWorktreePath(name string) string {
+	main := r.root
+	if trees, err := r.ListWorktrees(); err == nil && len(trees) > 0 {
+		main = trees[0].Path
+	}
+	suffix := strings.Trim(unsafePathChars.ReplaceAllString(name, "-"), "-")
+	return filepath.Join(filepath.Dir(main), filepath.Base(main)+"-"+suffix)
+}
+
+// AddWorktree checks out branch in a new worktree at path. A branch that only
+// exists on a remote is created tracking it; any other unknown name becomes a
+// new branch from HEAD.
+func (r *Repo) AddWorktree(path, branch string) error {
+	if r.BranchExists(branch) {
+		_, err := r.run("worktree", "add", path, branch)
+		return err
+	}
+	if ref := r.remoteBranch(branch); ref != "" {
+		_, err := r.run("worktree", "add", "--track", "-b", branch, path, ref)
+		return err
+	}
+	_, err := r.run("worktree", "add", "-b", branch, path)
+	return err
+}
+
+// AddPRWorktree fetches pull request number from the canonical remote into a
+// local pr-<number> branch and checks it out in a new worktree at path.
+func (r *Repo) AddPRWorktree(path string, number int) error {
+	remote := r.CanonicalRemote()
+	if remote == "" {
+		return fmt.Errorf("no remote to fetch pull requests from")
+	}
+	branch := fmt.Sprintf("pr-%d", number)
+	refspec := fmt.Sprintf("pull/%d/head:%s", number, branch)
+	if _, err := r.run("fetch", remote, refspec); err != nil {
+		return err
+	}
+	_, err := r.run("worktree", "add", path, branch)
+	return err
+}
+
+func (r *Repo) RemoveWorktree(path string, force bool) error {
+	args := []string{"worktree", "remove", path}
+	if force {
+		args = append(args, "--force")
+	}
+	_, err := r.run(args...)
+	return err
+}
+
+func (r *Repo) PruneWorktrees() error {
+	_, err := r.run("worktree", "prune")
+	return err
+}
+
+// BranchExists reports whether a local branch called name exists.
+func (r *Repo) BranchExists(name string) bool {
+	_, err := r.run("rev-parse", "--verify", "--quiet", "refs/heads/"+name)
+	return err == nil
+}
+
+// remoteBranch returns the first remote-tracking ref named <remote>/name.
+func (r *Repo) remoteBranch(name string) string {
+	for _, remote := range r.Remotes() {
+		ref := remote + "/" + name
+		if _, err := r.run("rev-parse", "--verify", "--quiet", "refs/remotes/"+ref); err == nil {
+			return ref
+		}
+	}
+	return ""
+}
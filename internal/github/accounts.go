@@ -0,0 +1,150 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// Account is a GitHub identity hit has a saved token for. Label is an
+// optional name such as "work" or "personal", set by editing accounts.json.
+type Account struct {
+	Host  string `json:"host"`
+	Login string `json:"login"`
+	Label string `json:"label,omitempty"`
+}
+
+// accountsConfig is the layout of accounts.json. Active maps each host to
+// the login used for it by default.
+type accountsConfig struct {
+	Accounts []Account         `json:"accounts"`
+	Active   map[string]string `json:"active,omitempty"`
+}
+
+func accountsFile() string {
+	return filepath.Join(ConfigDir(), "accounts.json")
+}
+
+// Accounts lists the saved accounts in the order they were added.
+func Accounts() ([]Account, error) {
+	cfg, err := readAccounts()
+	return cfg.Accounts, err
+}
+
+// ActiveAccount is the account last used on host, or the first one saved
+// for it.
+func ActiveAccount(host string) (Account, bool) {
+	cfg, _ := readAccounts()
+	var first *Account
+	for i, a := range cfg.Accounts {
+		if a.Host != host {
+			continue
+		}
+		if a.Login == cfg.Active[host] {
+			return a, true
+		}
+		if first == nil {
+			first = &cfg.Accounts[i]
+		}
+	}
+	if first != nil {
+		return *first, true
+	}
+	return Account{}, false
+}
+
+// SaveAccount stores the token of login on host, adds the account unless it
+// is known already and makes it the active one for host. It returns where
+// the token went.
+func SaveAccount(host, login, token string) (string, error) {
+	where, err := StoreToken(host, login, token)
+	if err != nil {
+		return "", err
+	}
+	cfg, err := readAccounts()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return where, err
+	}
+	if !slices.ContainsFunc(cfg.Accounts, func(a Account) bool { return a.Host == host && a.Login == login }) {
+		cfg.Accounts = append(cfg.Accounts, Account{Host: host, Login: login})
+	}
+	cfg.setActive(host, login)
+	return where, writeAccounts(cfg)
+}
+
+// UseAccount makes a the active account for its host.
+func UseAccount(a Account) error {
+	cfg, err := readAccounts()
+	if err != nil {
+		return err
+	}
+	cfg.setActive(a.Host, a.Login)
+	return writeAccounts(cfg)
+}
+
+// RemoveAccount forgets a and its token.
+func RemoveAccount(a Account) error {
+	if err := DeleteToken(a.Host, a.Login); err != nil {
+		return err
+	}
+	cfg, err := readAccounts()
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	cfg.Accounts = slices.DeleteFunc(cfg.Accounts, func(b Account) bool { return b.Host == a.Host && b.Login == a.Login })
+	if cfg.Active[a.Host] == a.Login {
+		delete(cfg.Active, a.Host)
+	}
+	return writeAccounts(cfg)
+}
+
+func (c *accountsConfig) setActive(host, login string) {
+	if c.Active == nil {
+		c.Active = map[string]string{}
+	}
+	c.Active[host] = login
+}
+
+func readAccounts() (accountsConfig, error) {
+	var cfg accountsConfig
+	data, err := os.ReadFile(accountsFile())
+	if err != nil {
+		return cfg, err
+	}
+	err = json.Unmarshal(data, &cfg)
+	return cfg, err
+}
+
+// writeAccounts replaces the file atomically. It holds no secrets, but is
+// kept private like the rest of the config directory.
+func writeAccounts(cfg accountsConfig) error {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	dir := ConfigDir()
+	if dir == "" {
+		return errors.New("no config directory")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "accounts-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), accountsFile())
+}
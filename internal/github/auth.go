@@ -1,8 +1,15 @@
 package github
 
 import (
-	"os"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
 
+	ghAPI "github.com/cli/go-gh/v2/pkg/api"
 	"github.com/cli/go-gh/v2/pkg/auth"
 )
 
@@ -10,20 +17,126 @@ type AuthStatus struct {
 	Authenticated bool
 	Token         string
 	Source        string
+	Host          string
 }
 
-func DetectAuth() AuthStatus {
-	if token := os.Getenv("GH_TOKEN"); token != "" {
-		return AuthStatus{Authenticated: true, Token: token, Source: "GH_TOKEN"}
+// DefaultHost is the host used outside a repository: GH_HOST, gh's
+// configured default, or github.com.
+func DefaultHost() string {
+	host, _ := auth.DefaultHost()
+	return host
+}
+
+// DetectAuth finds a token for host: GH_TOKEN or GITHUB_TOKEN for
+// github.com (GH_ENTERPRISE_TOKEN or GITHUB_ENTERPRISE_TOKEN for GitHub
+// Enterprise Server hosts), then the token of the active account on host,
+// then any other token saved by hit, then the gh CLI.
+func DetectAuth(host string) AuthStatus {
+	if host == "" {
+		host = DefaultHost()
+	}
+	if token, source := auth.TokenFromEnvOrConfig(host); token != "" && strings.HasSuffix(source, "_TOKEN") {
+		return AuthStatus{Authenticated: true, Token: token, Source: source, Host: host}
 	}
-	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
-		return AuthStatus{Authenticated: true, Token: token, Source: "GITHUB_TOKEN"}
+	if account, ok := ActiveAccount(host); ok {
+		if token, source := StoredToken(host, account.Login); token != "" {
+			return AuthStatus{Authenticated: true, Token: token, Source: source, Host: host}
+		}
 	}
+	if token, source := StoredToken(host, ""); token != "" {
+		return AuthStatus{Authenticated: true, Token: token, Source: source, Host: host}
+	}
+	if token, source := auth.TokenForHost(host); token != "" {
+		return AuthStatus{Authenticated: true, Token: token, Source: source, Host: host}
+	}
+	return AuthStatus{Host: host}
+}
 
-	token, source := auth.TokenForHost("github.com")
-	if token != "" {
-		return AuthStatus{Authenticated: true, Token: token, Source: source}
+// TokenInfo describes a token as GitHub reports it. Scopes is nil for
+// fine-grained tokens and GitHub Apps, which have no OAuth scopes; Expires is
+// zero for tokens that do not expire.
+type TokenInfo struct {
+	Login   string
+	Scopes  []string
+	Expires time.Time
+}
+
+// HasScope reports whether the token grants scope, counting broader scopes
+// that include it (repo includes public_repo, admin:org includes read:org).
+func (t TokenInfo) HasScope(scope string) bool {
+	if slices.Contains(t.Scopes, scope) {
+		return true
+	}
+	switch scope {
+	case "public_repo", "repo:status":
+		return slices.Contains(t.Scopes, "repo")
+	case "read:org":
+		return slices.Contains(t.Scopes, "write:org") || slices.Contains(t.Scopes, "admin:org")
 	}
+	return false
+}
 
-	return AuthStatus{}
+// ValidateToken checks token against GET /user on host. It fails for tokens
+// GitHub rejects, that have expired, or that cannot read repositories, and
+// whenever GitHub answers with an error. Only when no answer comes back at
+// all is the token accepted unchecked, with an empty TokenInfo.
+func ValidateToken(host, token string) (TokenInfo, error) {
+	if host == "" {
+		host = DefaultHost()
+	}
+	rest, err := ghAPI.NewRESTClient(ghAPI.ClientOptions{
+		Host:      host,
+		AuthToken: token,
+		Transport: newTransport(host, token, &rateTracker{}, false),
+	})
+	if err != nil {
+		return TokenInfo{}, err
+	}
+	resp, err := rest.Request(http.MethodGet, "user", nil)
+	if err != nil {
+		var httpErr *ghAPI.HTTPError
+		if !errors.As(err, &httpErr) {
+			return TokenInfo{}, nil
+		}
+		if httpErr.StatusCode == http.StatusUnauthorized {
+			return TokenInfo{}, errors.New("GitHub rejected the token; it is invalid, revoked or expired")
+		}
+		msg := httpErr.Message
+		if msg == "" {
+			msg = http.StatusText(httpErr.StatusCode)
+		}
+		return TokenInfo{}, fmt.Errorf("GitHub could not check the token: HTTP %d %s", httpErr.StatusCode, msg)
+	}
+	defer resp.Body.Close()
+
+	var user struct {
+		Login string `json:"login"`
+	}
+	json.NewDecoder(resp.Body).Decode(&user)
+	info := TokenInfo{Login: user.Login}
+
+	if header, ok := resp.Header["X-Oauth-Scopes"]; ok {
+		info.Scopes = []string{}
+		for _, s := range strings.Split(strings.Join(header, ","), ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				info.Scopes = append(info.Scopes, s)
+			}
+		}
+	}
+	if exp := resp.Header.Get("GitHub-Authentication-Token-Expiration"); exp != "" {
+		for _, layout := range []string{"2006-01-02 15:04:05 MST", "2006-01-02 15:04:05 -0700"} {
+			if t, err := time.Parse(layout, exp); err == nil {
+				info.Expires = t
+				break
+			}
+		}
+	}
+
+	if !info.Expires.IsZero() && time.Now().After(info.Expires) {
+		return info, fmt.Errorf("the token expired on %s", info.Expires.Format("2006-01-02"))
+	}
+	if info.Scopes != nil && !info.HasScope("public_repo") {
+		return info, errors.New("the token lacks the repo scope")
+	}
+	return info, nil
 }
@@ -0,0 +1,231 @@
+package github
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The cache is pruned once per run: entries not used for cacheMaxAge are
+// dropped, then the least recently used ones beyond cacheMaxEntries.
+const (
+	cacheMaxAge     = 30 * 24 * time.Hour
+	cacheMaxEntries = 5000
+)
+
+var pruneOnce sync.Once
+
+// errCacheMiss is returned for cache-only requests without a stored response.
+var errCacheMiss = errors.New("not cached")
+
+type cacheOnlyKey struct{}
+
+// cacheOnly marks a request to be answered from the on-disk cache without
+// touching the network.
+func cacheOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheOnlyKey{}, true)
+}
+
+// cacheTransport stores JSON GET responses on disk keyed by token and URL and
+// revalidates them with If-None-Match. A 304 is answered with the stored
+// response and does not count against the rate limit. GraphQL queries cannot
+// be revalidated; their latest response is kept only for cache-only reads.
+type cacheTransport struct {
+	dir  string
+	next http.RoundTripper
+}
+
+// CacheDir is where hit keeps cached API responses: $XDG_CACHE_HOME/hit, or
+// the platform cache directory when XDG_CACHE_HOME is unset.
+func CacheDir() string {
+	base := os.Getenv("XDG_CACHE_HOME")
+	if base == "" {
+		var err error
+		if base, err = os.UserCacheDir(); err != nil {
+			return ""
+		}
+	}
+	return filepath.Join(base, "hit")
+}
+
+func newCacheTransport(next http.RoundTripper) http.RoundTripper {
+	dir := CacheDir()
+	if dir == "" {
+		return next
+	}
+	t := &cacheTransport{dir: filepath.Join(dir, "http"), next: next}
+	pruneOnce.Do(func() { go t.prune(time.Now()) })
+	return t
+}
+
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	graphQL := req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/graphql") && req.GetBody != nil
+	if !graphQL && (req.Method != http.MethodGet || req.Header.Get("Range") != "") {
+		return t.next.RoundTrip(req)
+	}
+	path, err := t.path(req)
+	if err != nil {
+		return t.next.RoundTrip(req)
+	}
+	only, _ := req.Context().Value(cacheOnlyKey{}).(bool)
+	var cached *http.Response
+	if only || !graphQL {
+		cached, _ = t.load(path, req)
+	}
+
+	if only {
+		if cached == nil {
+			return nil, errCacheMiss
+		}
+		return cached, nil
+	}
+
+	if cached != nil {
+		if etag := cached.Header.Get("ETag"); etag != "" {
+			req = req.Clone(req.Context())
+			req.Header.Set("If-None-Match", etag)
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		resp.Body.Close()
+		// the modification time tracks use, for pruning
+		now := time.Now()
+		os.Chtimes(path, now, now)
+		// keep the fresh rate-limit and date headers
+		for k, v := range resp.Header {
+			if strings.HasPrefix(k, "X-Ratelimit-") || k == "Date" {
+				cached.Header[k] = v
+			}
+		}
+		return cached, nil
+	}
+
+	if resp.StatusCode == http.StatusOK && (graphQL || resp.Header.Get("ETag") != "") &&
+		strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		if err := t.store(path, resp); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// path locates the entry for req, keyed by its URL and, for GraphQL, the
+// query. The Authorization header is part of the key so accounts never see
+// each other's responses.
+func (t *cacheTransport) path(req *http.Request) (string, error) {
+	h := sha256.New()
+	io.WriteString(h, req.Header.Get("Authorization")+"\n"+req.URL.String()+"\n")
+	if req.Method == http.MethodPost {
+		body, err := req.GetBody()
+		if err != nil {
+			return "", err
+		}
+		defer body.Close()
+		if _, err := io.Copy(h, body); err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(t.dir, hex.EncodeToString(h.Sum(nil))), nil
+}
+
+func (t *cacheTransport) load(path string, req *http.Request) (*http.Response, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(data)), req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// store writes resp to disk, leaving its body readable for the caller.
+// Failing to write the cache never fails the request.
+func (t *cacheTransport) store(path string, resp *http.Response) error {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	dump, err := httputil.DumpResponse(resp, true)
+	if err != nil {
+		return nil
+	}
+	if err := os.MkdirAll(t.dir, 0o700); err != nil {
+		return nil
+	}
+	tmp, err := os.CreateTemp(t.dir, "tmp-*")
+	if err != nil {
+		return nil
+	}
+	_, werr := tmp.Write(dump)
+	cerr := tmp.Close()
+	if werr != nil || cerr != nil {
+		os.Remove(tmp.Name())
+		return nil
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+	}
+	return nil
+}
+
+// prune removes entries not used for cacheMaxAge, then the least recently
+// used ones until at most cacheMaxEntries are left.
+func (t *cacheTransport) prune(now time.Time) {
+	dirEntries, err := os.ReadDir(t.dir)
+	if err != nil {
+		return
+	}
+	type entry struct {
+		path string
+		used time.Time
+	}
+	var kept []entry
+	for _, e := range dirEntries {
+		info, err := e.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		path := filepath.Join(t.dir, e.Name())
+		if now.Sub(info.ModTime()) > cacheMaxAge {
+			os.Remove(path)
+			continue
+		}
+		kept = append(kept, entry{path: path, used: info.ModTime()})
+	}
+	if len(kept) <= cacheMaxEntries {
+		return
+	}
+	slices.SortFunc(kept, func(a, b entry) int { return b.used.Compare(a.used) })
+	for _, e := range kept[cacheMaxEntries:] {
+		os.Remove(e.path)
+	}
+}
@@ -15,26 +15,19 @@ type jobsResponse struct {
 	Jobs       []Job `json:"jobs"`
 }
 
-func (c *Client) GetRuns(branch string, perPage int) ([]WorkflowRun, error) {
+// Runs pages through the workflow runs of branch, newest first.
+func (c *Client) Runs(branch string, perPage int) *Pager[WorkflowRun] {
 	params := url.Values{}
 	params.Set("branch", branch)
 	params.Set("per_page", fmt.Sprintf("%d", perPage))
-
-	var resp runsResponse
-	err := c.rest.Get(c.endpoint("actions/runs")+"?"+params.Encode(), &resp)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch workflow runs: %w", err)
-	}
-	return resp.Runs, nil
+	return newWrappedPager(c, c.endpoint("actions/runs")+"?"+params.Encode(), "workflow runs",
+		func(r runsResponse) []WorkflowRun { return r.Runs })
 }
 
 func (c *Client) GetJobs(runID int64) ([]Job, error) {
-	var resp jobsResponse
-	err := c.rest.Get(c.endpoint(fmt.Sprintf("actions/runs/%d/jobs", runID)), &resp)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch jobs: %w", err)
-	}
-	return resp.Jobs, nil
+	pager := newWrappedPager(c, c.endpoint(fmt.Sprintf("actions/runs/%d/jobs?per_page=100", runID)), "jobs",
+		func(r jobsResponse) []Job { return r.Jobs })
+	return pager.All()
 }
 
 func (c *Client) GetJobLog(jobID int64) (string, error) {
@@ -2,45 +2,94 @@ package github
 
 import (
 	"fmt"
+	"net/http"
+	"strings"
 
 	ghAPI "github.com/cli/go-gh/v2/pkg/api"
 )
 
 type Client struct {
 	rest  *ghAPI.RESTClient
+	gql   *ghAPI.GraphQLClient
+	rate  *rateTracker
+	host  string
 	owner string
 	repo  string
 }
 
-func NewClient(owner, repo, token string) (*Client, error) {
+// NewClient talks to host, which is github.com or a GitHub Enterprise Server
+// host (API under /api/v3); an empty host means DefaultHost.
+func NewClient(host, owner, repo, token string) (*Client, error) {
+	if host == "" {
+		host = DefaultHost()
+	}
+	rate := &rateTracker{backoff: make(chan struct{}, 1)}
 	opts := ghAPI.ClientOptions{
+		Host:      host,
 		AuthToken: token,
+		Transport: newTransport(host, token, rate, true),
 	}
 	rest, err := ghAPI.NewRESTClient(opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create GitHub client: %w", err)
 	}
-	return &Client{rest: rest, owner: owner, repo: repo}, nil
+	gql, err := ghAPI.NewGraphQLClient(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitHub client: %w", err)
+	}
+	return &Client{rest: rest, gql: gql, rate: rate, host: host, owner: owner, repo: repo}, nil
 }
 
-func (c *Client) endpoint(path string) string {
-	return fmt.Sprintf("repos/%s/%s/%s", c.owner, c.repo, path)
+// newTransport chains the transports every API request goes through:
+// authentication, rate-limit tracking with retries and, if cached, the
+// on-disk response cache.
+func newTransport(host, token string, rate *rateTracker, cached bool) http.RoundTripper {
+	next := http.DefaultTransport
+	if cached {
+		next = newCacheTransport(next)
+	}
+	return &authTransport{host: host, token: token, next: &retryTransport{next: next, rate: rate}}
 }
 
-func (c *Client) GetUserOrgs() ([]Org, error) {
-	var orgs []Org
-	err := c.rest.Get("user/orgs?per_page=100", &orgs)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch user orgs: %w", err)
-	}
-	return orgs, nil
+// authTransport sends the token to hosts with a port, such as
+// ghe.example.com:8443. go-gh compares hostnames without the port and
+// leaves those requests unauthenticated.
+type authTransport struct {
+	host  string
+	token string
+	next  http.RoundTripper
 }
 
-func (c *Client) GetOrgRepos(org string) ([]OrgRepo, error) {
-	var repos []OrgRepo
-	err := c.rest.Get(fmt.Sprintf("orgs/%s/repos?sort=updated&per_page=100", org), &repos)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch org repos: %w", err)
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.token != "" && req.Header.Get("Authorization") == "" && strings.EqualFold(req.URL.Host, t.host) {
+		req = req.Clone(req.Context())
+		req.Header.Set("Authorization", "token "+t.token)
 	}
-	return repos, nil
+	return t.next.RoundTrip(req)
+}
+
+// Host is the GitHub host the client talks to.
+func (c *Client) Host() string {
+	return c.host
+}
+
+// RateLimit returns the quota reported by the latest response and whether a
+// request is currently backing off.
+func (c *Client) RateLimit() RateLimit {
+	return c.rate.get()
+}
+
+// Backoffs receives whenever a request starts backing off, so a countdown
+// can be shown for as long as RateLimit reports Waiting.
+func (c *Client) Backoffs() <-chan struct{} {
+	return c.rate.backoff
+}
+
+func (c *Client) endpoint(path string) string {
+	return fmt.Sprintf("repos/%s/%s/%s", c.owner, c.repo, path)
+}
+
+// UserOrgs pages through the organizations the user belongs to.
+func (c *Client) UserOrgs() *Pager[Org] {
+	return newPager[Org](c, "user/orgs?per_page=100", "user orgs")
 }
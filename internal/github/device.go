@@ -0,0 +1,161 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// OAuthClientID identifies hit's OAuth app for the device flow. Release
+// builds set it with -ldflags "-X .../internal/github.OAuthClientID=...";
+// HIT_OAUTH_CLIENT_ID overrides it, e.g. for an app registered on a GitHub
+// Enterprise Server.
+var OAuthClientID = ""
+
+// oauthScopes covers the repository, CI and org views.
+var oauthScopes = []string{"repo", "read:org", "workflow"}
+
+// DeviceFlow signs a user in with the OAuth device flow: the user enters a
+// short code at a verification URL while hit polls for the token.
+type DeviceFlow struct {
+	// BaseURL is the web root of the host, such as https://github.com.
+	BaseURL  string
+	ClientID string
+	Scopes   []string
+	HTTP     *http.Client
+}
+
+// DeviceCode is what the user needs to authorize hit in the browser.
+type DeviceCode struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURI string `json:"verification_uri"`
+	ExpiresIn       int    `json:"expires_in"`
+	Interval        int    `json:"interval"`
+}
+
+// NewDeviceFlow prepares the device flow for host. It fails when no OAuth
+// client ID is configured.
+func NewDeviceFlow(host string) (*DeviceFlow, error) {
+	clientID := oauthClientID()
+	if clientID == "" {
+		return nil, fmt.Errorf("no OAuth client ID configured (set HIT_OAUTH_CLIENT_ID)")
+	}
+	if host == "" {
+		host = DefaultHost()
+	}
+	return &DeviceFlow{
+		BaseURL:  "https://" + host,
+		ClientID: clientID,
+		Scopes:   oauthScopes,
+		HTTP:     http.DefaultClient,
+	}, nil
+}
+
+// DeviceFlowAvailable reports whether an OAuth client ID is configured, so
+// that browser sign-in can be offered.
+func DeviceFlowAvailable() bool {
+	return oauthClientID() != ""
+}
+
+func oauthClientID() string {
+	if id := os.Getenv("HIT_OAUTH_CLIENT_ID"); id != "" {
+		return id
+	}
+	return OAuthClientID
+}
+
+// RequestCode starts the flow.
+func (f *DeviceFlow) RequestCode(ctx context.Context) (*DeviceCode, error) {
+	form := url.Values{}
+	form.Set("client_id", f.ClientID)
+	form.Set("scope", strings.Join(f.Scopes, " "))
+
+	var code DeviceCode
+	if err := f.post(ctx, "/login/device/code", form, &code); err != nil {
+		return nil, fmt.Errorf("failed to request device code: %w", err)
+	}
+	if code.DeviceCode == "" {
+		return nil, fmt.Errorf("failed to request device code: empty response")
+	}
+	if code.Interval <= 0 {
+		code.Interval = 5
+	}
+	return &code, nil
+}
+
+// PollToken waits until the user authorizes the code and returns the access
+// token. It gives up when the code expires, access is denied or ctx ends.
+func (f *DeviceFlow) PollToken(ctx context.Context, code *DeviceCode) (string, error) {
+	form := url.Values{}
+	form.Set("client_id", f.ClientID)
+	form.Set("device_code", code.DeviceCode)
+	form.Set("grant_type", "urn:ietf:params:oauth:grant-type:device_code")
+
+	interval := time.Duration(code.Interval) * time.Second
+	deadline := time.Now().Add(time.Duration(code.ExpiresIn) * time.Second)
+	for {
+		if code.ExpiresIn > 0 && time.Now().After(deadline) {
+			return "", errors.New("the code expired; start again")
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(interval):
+		}
+
+		var resp struct {
+			AccessToken string `json:"access_token"`
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+			Interval    int    `json:"interval"`
+		}
+		if err := f.post(ctx, "/login/oauth/access_token", form, &resp); err != nil {
+			return "", fmt.Errorf("failed to poll for token: %w", err)
+		}
+		switch resp.Error {
+		case "":
+			if resp.AccessToken == "" {
+				return "", errors.New("failed to poll for token: empty response")
+			}
+			return resp.AccessToken, nil
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * time.Second
+			if resp.Interval > 0 {
+				interval = time.Duration(resp.Interval) * time.Second
+			}
+		case "expired_token":
+			return "", errors.New("the code expired; start again")
+		case "access_denied":
+			return "", errors.New("authorization was denied")
+		default:
+			return "", fmt.Errorf("%s: %s", resp.Error, resp.Description)
+		}
+	}
+}
+
+func (f *DeviceFlow) post(ctx context.Context, path string, form url.Values, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(f.BaseURL, "/")+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := f.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,123 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// deviceServer stands in for GitHub's device flow endpoints. Each poll of
+// the token endpoint gets the next of responses and its arrival time is
+// recorded.
+type deviceServer struct {
+	*httptest.Server
+	mu        sync.Mutex
+	responses []map[string]any
+	polls     []time.Time
+}
+
+func newDeviceServer(t *testing.T, responses ...map[string]any) *deviceServer {
+	s := &deviceServer{responses: responses}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login/device/code", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("client_id") != "client" {
+			t.Errorf("client_id = %q", r.FormValue("client_id"))
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"device_code":      "dev",
+			"user_code":        "ABCD-1234",
+			"verification_uri": s.URL + "/login/device",
+			"interval":         1,
+		})
+	})
+	mux.HandleFunc("/login/oauth/access_token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("device_code") != "dev" {
+			t.Errorf("device_code = %q", r.FormValue("device_code"))
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.polls = append(s.polls, time.Now())
+		if len(s.responses) == 0 {
+			t.Error("unexpected poll")
+			json.NewEncoder(w).Encode(map[string]any{"error": "access_denied"})
+			return
+		}
+		json.NewEncoder(w).Encode(s.responses[0])
+		s.responses = s.responses[1:]
+	})
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *deviceServer) flow() *DeviceFlow {
+	return &DeviceFlow{BaseURL: s.URL, ClientID: "client", Scopes: oauthScopes, HTTP: s.Client()}
+}
+
+func TestDeviceFlowSlowDown(t *testing.T) {
+	s := newDeviceServer(t,
+		map[string]any{"error": "authorization_pending"},
+		map[string]any{"error": "slow_down", "interval": 2},
+		map[string]any{"access_token": "gho_token"},
+	)
+	f := s.flow()
+	code, err := f.RequestCode(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code.UserCode != "ABCD-1234" || code.Interval != 1 {
+		t.Fatalf("code = %+v", code)
+	}
+
+	token, err := f.PollToken(context.Background(), code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "gho_token" {
+		t.Errorf("token = %q", token)
+	}
+	if len(s.polls) != 3 {
+		t.Fatalf("polled %d times, want 3", len(s.polls))
+	}
+	before, after := s.polls[1].Sub(s.polls[0]), s.polls[2].Sub(s.polls[1])
+	if after < 2*time.Second || after <= before {
+		t.Errorf("interval after slow_down = %s, before = %s; want it to grow to 2s", after, before)
+	}
+}
+
+func TestDeviceFlowErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		resp map[string]any
+		want string
+	}{
+		{"expired", map[string]any{"error": "expired_token"}, "the code expired; start again"},
+		{"denied", map[string]any{"error": "access_denied"}, "authorization was denied"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newDeviceServer(t, tt.resp)
+			_, err := s.flow().PollToken(context.Background(), &DeviceCode{DeviceCode: "dev", Interval: 1})
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeviceFlowCancel(t *testing.T) {
+	s := newDeviceServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := s.flow().PollToken(ctx, &DeviceCode{DeviceCode: "dev", Interval: 1})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if len(s.polls) != 0 {
+		t.Errorf("polled %d times after cancel", len(s.polls))
+	}
+}
@@ -0,0 +1,123 @@
+package github
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	ghAPI "github.com/cli/go-gh/v2/pkg/api"
+)
+
+// CheckStatus is whether a feature works with the current token.
+type CheckStatus int
+
+const (
+	CheckUnknown CheckStatus = iota
+	CheckOK
+	// CheckLimited means the feature works for public repositories only.
+	CheckLimited
+	CheckMissing
+)
+
+// FeatureCheck tells whether a feature works with the current token and,
+// when it does not, which scope or permission it needs.
+type FeatureCheck struct {
+	Feature string
+	Status  CheckStatus
+	Need    string
+	Note    string
+}
+
+// CheckFeatures reports which features work with the token described by
+// info. Classic tokens are judged by their scopes. Fine-grained tokens and
+// GitHub App tokens have no scopes, so the read features are probed against
+// the client's repository; write permissions cannot be probed without side
+// effects and are reported as unknown.
+func (c *Client) CheckFeatures(info TokenInfo) []FeatureCheck {
+	checks := []FeatureCheck{
+		{Feature: "CI logs"},
+		{Feature: "Org listing"},
+		{Feature: "PR merge"},
+		{Feature: "Workflow dispatch"},
+	}
+	switch {
+	case info.Login == "":
+		for i := range checks {
+			checks[i].Note = "the token could not be checked with GitHub"
+		}
+	case info.Scopes != nil:
+		for i := range checks {
+			if checks[i].Feature == "Org listing" {
+				checks[i] = orgScopeCheck(checks[i], info)
+			} else {
+				checks[i] = repoScopeCheck(checks[i], info)
+			}
+		}
+	default:
+		if c.owner != "" {
+			checks[0] = c.probe(checks[0], "actions: read", c.endpoint("actions/runs?per_page=1"))
+		} else {
+			checks[0].Need = "actions: read"
+			checks[0].Note = "open a GitHub repository to check"
+		}
+		checks[1] = c.probe(checks[1], "organization members: read", "user/orgs?per_page=1")
+		checks[2].Need = "pull requests: write, contents: write"
+		checks[2].Note = "checked when used"
+		checks[3].Need = "actions: write"
+		checks[3].Note = "checked when used"
+	}
+	return checks
+}
+
+func repoScopeCheck(check FeatureCheck, info TokenInfo) FeatureCheck {
+	check.Need = "repo"
+	switch {
+	case info.HasScope("repo"):
+		check.Status = CheckOK
+	case info.HasScope("public_repo"):
+		check.Status = CheckLimited
+		check.Note = "public repositories only"
+	default:
+		check.Status = CheckMissing
+	}
+	return check
+}
+
+func orgScopeCheck(check FeatureCheck, info TokenInfo) FeatureCheck {
+	check.Need = "read:org"
+	if info.HasScope("read:org") || info.HasScope("user") {
+		check.Status = CheckOK
+		return check
+	}
+	check.Status = CheckMissing
+	check.Note = "only organizations with public membership are listed"
+	return check
+}
+
+// probe requests path and marks check missing when GitHub refuses it. The
+// permission GitHub names in X-Accepted-GitHub-Permissions takes precedence
+// over need.
+func (c *Client) probe(check FeatureCheck, need, path string) FeatureCheck {
+	check.Need = need
+	resp, err := c.rest.Request(http.MethodGet, path, nil)
+	if err == nil {
+		resp.Body.Close()
+		check.Status = CheckOK
+		return check
+	}
+	var httpErr *ghAPI.HTTPError
+	if !errors.As(err, &httpErr) {
+		check.Note = "GitHub could not be reached to check the token"
+		return check
+	}
+	switch httpErr.StatusCode {
+	case http.StatusForbidden, http.StatusNotFound:
+		check.Status = CheckMissing
+		if accepted := httpErr.Headers.Get("X-Accepted-Github-Permissions"); accepted != "" {
+			check.Need = strings.ReplaceAll(accepted, "=", ": ")
+		}
+	default:
+		check.Note = httpErr.Message
+	}
+	return check
+}
@@ -0,0 +1,287 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"time"
+)
+
+const pullRequestsQuery = `query($owner: String!, $name: String!, $cursor: String) {
+  repository(owner: $owner, name: $name) {
+    pullRequests(first: 50, after: $cursor, states: OPEN, orderBy: {field: UPDATED_AT, direction: DESC}) {
+      nodes {
+        number
+        title
+        url
+        isDraft
+        headRefName
+        baseRefName
+        updatedAt
+        reviewDecision
+        author { login }
+        reviewRequests { totalCount }
+        commits(last: 1) { nodes { commit { statusCheckRollup { state } } } }
+      }
+      pageInfo { hasNextPage endCursor }
+    }
+  }
+}`
+
+// repoFragment selects what the repository lists show, including the
+// default branch and its check rollup.
+const repoFragment = `
+fragment repo on Repository {
+  name
+  nameWithOwner
+  description
+  sshUrl
+  isPrivate
+  isArchived
+  updatedAt
+  defaultBranchRef {
+    name
+    target { ... on Commit { statusCheckRollup { state } } }
+  }
+}`
+
+const orgReposQuery = `query($org: String!, $cursor: String) {
+  organization(login: $org) {
+    repositories(first: 100, after: $cursor, orderBy: {field: UPDATED_AT, direction: DESC}) {
+      nodes { ...repo }
+      pageInfo { hasNextPage endCursor }
+    }
+  }
+}` + repoFragment
+
+const viewerReposQuery = `query($affiliations: [RepositoryAffiliation], $cursor: String) {
+  viewer {
+    repositories(first: 100, after: $cursor, ownerAffiliations: $affiliations, orderBy: {field: UPDATED_AT, direction: DESC}) {
+      nodes { ...repo }
+      pageInfo { hasNextPage endCursor }
+    }
+  }
+}` + repoFragment
+
+const starredReposQuery = `query($cursor: String) {
+  viewer {
+    starredRepositories(first: 100, after: $cursor, orderBy: {field: STARRED_AT, direction: DESC}) {
+      nodes { ...repo }
+      pageInfo { hasNextPage endCursor }
+    }
+  }
+}` + repoFragment
+
+const searchReposQuery = `query($query: String!, $cursor: String) {
+  search(query: $query, type: REPOSITORY, first: 100, after: $cursor) {
+    nodes { ...repo }
+    pageInfo { hasNextPage endCursor }
+  }
+}` + repoFragment
+
+type pageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+type checkRollup struct {
+	State string `json:"state"`
+}
+
+type pullRequestsResponse struct {
+	Repository struct {
+		PullRequests struct {
+			Nodes []struct {
+				Number         int       `json:"number"`
+				Title          string    `json:"title"`
+				URL            string    `json:"url"`
+				IsDraft        bool      `json:"isDraft"`
+				HeadRefName    string    `json:"headRefName"`
+				BaseRefName    string    `json:"baseRefName"`
+				UpdatedAt      time.Time `json:"updatedAt"`
+				ReviewDecision string    `json:"reviewDecision"`
+				Author         struct {
+					Login string `json:"login"`
+				} `json:"author"`
+				ReviewRequests struct {
+					TotalCount int `json:"totalCount"`
+				} `json:"reviewRequests"`
+				Commits struct {
+					Nodes []struct {
+						Commit struct {
+							StatusCheckRollup *checkRollup `json:"statusCheckRollup"`
+						} `json:"commit"`
+					} `json:"nodes"`
+				} `json:"commits"`
+			} `json:"nodes"`
+			PageInfo pageInfo `json:"pageInfo"`
+		} `json:"pullRequests"`
+	} `json:"repository"`
+}
+
+// repoNode is a repository selected with repoFragment.
+type repoNode struct {
+	Name             string    `json:"name"`
+	NameWithOwner    string    `json:"nameWithOwner"`
+	Description      string    `json:"description"`
+	SSHURL           string    `json:"sshUrl"`
+	IsPrivate        bool      `json:"isPrivate"`
+	IsArchived       bool      `json:"isArchived"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+	DefaultBranchRef *struct {
+		Name   string `json:"name"`
+		Target struct {
+			StatusCheckRollup *checkRollup `json:"statusCheckRollup"`
+		} `json:"target"`
+	} `json:"defaultBranchRef"`
+}
+
+type repoConnection struct {
+	Nodes    []repoNode `json:"nodes"`
+	PageInfo pageInfo   `json:"pageInfo"`
+}
+
+type orgReposResponse struct {
+	Organization struct {
+		Repositories repoConnection `json:"repositories"`
+	} `json:"organization"`
+}
+
+type viewerReposResponse struct {
+	Viewer struct {
+		Repositories repoConnection `json:"repositories"`
+	} `json:"viewer"`
+}
+
+type starredReposResponse struct {
+	Viewer struct {
+		StarredRepositories repoConnection `json:"starredRepositories"`
+	} `json:"viewer"`
+}
+
+type searchReposResponse struct {
+	Search repoConnection `json:"search"`
+}
+
+// repos converts a page of repository nodes. Search results that are not
+// repositories come back empty and are skipped.
+func (conn repoConnection) repos() ([]OrgRepo, pageInfo) {
+	repos := make([]OrgRepo, 0, len(conn.Nodes))
+	for _, n := range conn.Nodes {
+		if n.NameWithOwner == "" {
+			continue
+		}
+		repo := OrgRepo{
+			Name:        n.Name,
+			FullName:    n.NameWithOwner,
+			Description: n.Description,
+			SSHURL:      n.SSHURL,
+			Private:     n.IsPrivate,
+			Archived:    n.IsArchived,
+			UpdatedAt:   n.UpdatedAt,
+		}
+		if ref := n.DefaultBranchRef; ref != nil {
+			repo.DefaultBranch = ref.Name
+			if ref.Target.StatusCheckRollup != nil {
+				repo.CIStatus = ref.Target.StatusCheckRollup.State
+			}
+		}
+		repos = append(repos, repo)
+	}
+	return repos, conn.PageInfo
+}
+
+// newGraphQLPager pages through a connection with cursor-based pagination.
+// The query takes a $cursor variable; page extracts the items and page info
+// from one response.
+func newGraphQLPager[T, R any](c *Client, query string, vars map[string]any, what string, page func(R) ([]T, pageInfo)) *Pager[T] {
+	fetch := func(ctx context.Context, cursor string) ([]T, string, error) {
+		v := maps.Clone(vars)
+		if cursor != "" {
+			v["cursor"] = cursor
+		}
+		var resp R
+		if err := c.gql.DoWithContext(ctx, query, v, &resp); err != nil {
+			return nil, "", fmt.Errorf("failed to fetch %s: %w", what, err)
+		}
+		items, info := page(resp)
+		if !info.HasNextPage {
+			return items, "", nil
+		}
+		return items, info.EndCursor, nil
+	}
+	return &Pager[T]{fetch: fetch}
+}
+
+// PullRequests pages through the repository's open pull requests with their
+// review decision and check rollup, most recently updated first.
+func (c *Client) PullRequests() *Pager[PullRequest] {
+	vars := map[string]any{"owner": c.owner, "name": c.repo}
+	return newGraphQLPager(c, pullRequestsQuery, vars, "pull requests", func(r pullRequestsResponse) ([]PullRequest, pageInfo) {
+		conn := r.Repository.PullRequests
+		prs := make([]PullRequest, len(conn.Nodes))
+		for i, n := range conn.Nodes {
+			prs[i] = PullRequest{
+				Number:           n.Number,
+				Title:            n.Title,
+				URL:              n.URL,
+				Author:           n.Author.Login,
+				HeadRef:          n.HeadRefName,
+				BaseRef:          n.BaseRefName,
+				Draft:            n.IsDraft,
+				UpdatedAt:        n.UpdatedAt,
+				ReviewDecision:   n.ReviewDecision,
+				ReviewsRequested: n.ReviewRequests.TotalCount,
+			}
+			if len(n.Commits.Nodes) > 0 && n.Commits.Nodes[0].Commit.StatusCheckRollup != nil {
+				prs[i].Checks = n.Commits.Nodes[0].Commit.StatusCheckRollup.State
+			}
+		}
+		return prs, conn.PageInfo
+	})
+}
+
+// OrgRepos pages through an organization's repositories, most recently
+// updated first, with the default branch and its check rollup.
+func (c *Client) OrgRepos(org string) *Pager[OrgRepo] {
+	vars := map[string]any{"org": org}
+	return newGraphQLPager(c, orgReposQuery, vars, "org repos", func(r orgReposResponse) ([]OrgRepo, pageInfo) {
+		return r.Organization.Repositories.repos()
+	})
+}
+
+// UserRepos pages through the repositories the user owns, most recently
+// updated first.
+func (c *Client) UserRepos() *Pager[OrgRepo] {
+	return c.viewerRepos("OWNER", "user repos")
+}
+
+// CollaboratorRepos pages through repositories owned by others that the
+// user collaborates on, most recently updated first.
+func (c *Client) CollaboratorRepos() *Pager[OrgRepo] {
+	return c.viewerRepos("COLLABORATOR", "collaborator repos")
+}
+
+func (c *Client) viewerRepos(affiliation, what string) *Pager[OrgRepo] {
+	vars := map[string]any{"affiliations": []string{affiliation}}
+	return newGraphQLPager(c, viewerReposQuery, vars, what, func(r viewerReposResponse) ([]OrgRepo, pageInfo) {
+		return r.Viewer.Repositories.repos()
+	})
+}
+
+// StarredRepos pages through the repositories the user starred, most
+// recently starred first.
+func (c *Client) StarredRepos() *Pager[OrgRepo] {
+	return newGraphQLPager(c, starredReposQuery, map[string]any{}, "starred repos", func(r starredReposResponse) ([]OrgRepo, pageInfo) {
+		return r.Viewer.StarredRepositories.repos()
+	})
+}
+
+// SearchRepos pages through the repositories matching query, which takes
+// GitHub's search syntax, e.g. "cli topic:terminal language:go org:acme".
+func (c *Client) SearchRepos(query string) *Pager[OrgRepo] {
+	vars := map[string]any{"query": query}
+	return newGraphQLPager(c, searchReposQuery, vars, "repository search", func(r searchReposResponse) ([]OrgRepo, pageInfo) {
+		return r.Search.repos()
+	})
+}
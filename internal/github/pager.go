@@ -0,0 +1,112 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+)
+
+var nextLinkRE = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// fetchPage fetches the page at cursor and returns its items and the cursor
+// of the following page, or "" after the last page.
+type fetchPage[T any] func(ctx context.Context, cursor string) ([]T, string, error)
+
+// Pager walks a paginated list one page at a time, either by following the
+// REST Link: rel="next" header or a GraphQL cursor, so views can fetch more
+// as the user scrolls.
+type Pager[T any] struct {
+	fetch fetchPage[T]
+	first string
+	next  string
+	done  bool
+}
+
+// newPager pages through a REST endpoint that returns a JSON array.
+func newPager[T any](c *Client, path, what string) *Pager[T] {
+	return newRESTPager(c, path, what, func(r io.Reader) ([]T, error) {
+		var items []T
+		err := json.NewDecoder(r).Decode(&items)
+		return items, err
+	})
+}
+
+// newWrappedPager pages through a REST endpoint that wraps its items in an
+// object, such as {"total_count": 3, "workflow_runs": [...]}.
+func newWrappedPager[T, W any](c *Client, path, what string, items func(W) []T) *Pager[T] {
+	return newRESTPager(c, path, what, func(r io.Reader) ([]T, error) {
+		var wrapper W
+		if err := json.NewDecoder(r).Decode(&wrapper); err != nil {
+			return nil, err
+		}
+		return items(wrapper), nil
+	})
+}
+
+func newRESTPager[T any](c *Client, path, what string, decode func(io.Reader) ([]T, error)) *Pager[T] {
+	fetch := func(ctx context.Context, url string) ([]T, string, error) {
+		resp, err := c.rest.RequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to fetch %s: %w", what, err)
+		}
+		defer resp.Body.Close()
+
+		items, err := decode(resp.Body)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to decode %s: %w", what, err)
+		}
+		var next string
+		if m := nextLinkRE.FindStringSubmatch(resp.Header.Get("Link")); m != nil {
+			next = m[1]
+		}
+		return items, next, nil
+	}
+	return &Pager[T]{fetch: fetch, first: path, next: path}
+}
+
+// HasNext reports whether another page can be fetched.
+func (p *Pager[T]) HasNext() bool {
+	return !p.done
+}
+
+// Next fetches the next page. It returns no items once the list is
+// exhausted.
+func (p *Pager[T]) Next() ([]T, error) {
+	if p.done {
+		return nil, nil
+	}
+	items, next, err := p.fetch(context.Background(), p.next)
+	if err != nil {
+		return nil, err
+	}
+	p.next = next
+	p.done = next == ""
+	return items, nil
+}
+
+// Cached returns the first page as last stored in the on-disk cache without
+// a network request, so a view can render while Next refreshes it. It does
+// not advance the pager.
+func (p *Pager[T]) Cached() ([]T, bool) {
+	items, _, err := p.fetch(cacheOnly(context.Background()), p.first)
+	if err != nil {
+		return nil, false
+	}
+	return items, true
+}
+
+// All fetches every remaining page.
+func (p *Pager[T]) All() ([]T, error) {
+	var all []T
+	for p.HasNext() {
+		items, err := p.Next()
+		if err != nil {
+			return all, err
+		}
+		all = append(all, items...)
+	}
+	return all, nil
+}
@@ -0,0 +1,179 @@
+package github
+
+import (
+	"bytes"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxRetries bounds how often one request is retried.
+	maxRetries = 4
+	// maxWait is the longest hit waits before a retry; a primary limit that
+	// resets later than this is reported instead.
+	maxWait = time.Minute
+)
+
+// RateLimit is the API quota as last reported by GitHub.
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+	// RetryAt is set while a request is held back after a rate limit or a
+	// server error, with RetryReason saying which.
+	RetryAt     time.Time
+	RetryReason string
+}
+
+// Known reports whether any response has carried rate-limit headers yet.
+func (r RateLimit) Known() bool {
+	return r.Limit > 0
+}
+
+// Low reports whether less than a tenth of the quota is left.
+func (r RateLimit) Low() bool {
+	return r.Known() && r.Remaining*10 < r.Limit
+}
+
+// Waiting reports whether a request is currently backing off.
+func (r RateLimit) Waiting() bool {
+	return time.Now().Before(r.RetryAt)
+}
+
+// rateTracker records the quota; backoff, when set, is signalled whenever a
+// request starts waiting before a retry.
+type rateTracker struct {
+	mu      sync.Mutex
+	limit   RateLimit
+	backoff chan struct{}
+}
+
+func (t *rateTracker) get() RateLimit {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.limit
+}
+
+func (t *rateTracker) observe(h http.Header) {
+	limit, err := strconv.Atoi(h.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return
+	}
+	remaining, _ := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	reset, _ := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.limit.Limit = limit
+	t.limit.Remaining = remaining
+	t.limit.Reset = time.Unix(reset, 0)
+}
+
+func (t *rateTracker) waiting(until time.Time, reason string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.limit.RetryAt = until
+	t.limit.RetryReason = reason
+	if !until.IsZero() {
+		select {
+		case t.backoff <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// retryTransport records the rate limit from every response and retries
+// requests that hit a rate limit or a server error, backing off with jitter.
+type retryTransport struct {
+	next http.RoundTripper
+	rate *rateTracker
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		t.rate.observe(resp.Header)
+
+		wait, reason := retryDelay(resp, attempt)
+		if reason == "" || attempt == maxRetries || wait > maxWait {
+			return resp, nil
+		}
+		if req.Body != nil {
+			if req.GetBody == nil {
+				return resp, nil
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return resp, nil
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		t.rate.waiting(time.Now().Add(wait), reason)
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			t.rate.waiting(time.Time{}, "")
+			return nil, req.Context().Err()
+		}
+		t.rate.waiting(time.Time{}, "")
+	}
+}
+
+// retryDelay decides whether resp should be retried and after how long. It
+// returns an empty reason for responses that are final.
+func retryDelay(resp *http.Response, attempt int) (time.Duration, string) {
+	switch {
+	case resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests:
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			return time.Duration(secs)*time.Second + jitter(time.Second), "rate limited"
+		}
+		if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			reset, _ := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+			return time.Until(time.Unix(reset, 0)) + jitter(time.Second), "rate limited"
+		}
+		if isSecondaryLimit(resp) {
+			// GitHub asks to wait at least a minute when it gives no time
+			return maxWait, "rate limited"
+		}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return backoff(attempt), "rate limited"
+		}
+	case resp.StatusCode >= 500:
+		return backoff(attempt), "GitHub unavailable"
+	}
+	return 0, ""
+}
+
+// isSecondaryLimit recognizes a secondary rate limit by its message, leaving
+// the body readable for the caller.
+func isSecondaryLimit(resp *http.Response) bool {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return err == nil && strings.Contains(strings.ToLower(string(body)), "secondary rate limit")
+}
+
+// backoff doubles from one second per attempt, spread over the upper half of
+// the interval so clients retrying together drift apart.
+func backoff(attempt int) time.Duration {
+	d := time.Second << attempt
+	return d/2 + jitter(d/2)
+}
+
+func jitter(max time.Duration) time.Duration {
+	return time.Duration(rand.Int64N(int64(max)))
+}
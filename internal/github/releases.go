@@ -0,0 +1,50 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+type generatedNotes struct {
+	Name string `json:"name"`
+	Body string `json:"body"`
+}
+
+func (c *Client) GetReleases() ([]Release, error) {
+	return newPager[Release](c, c.endpoint("releases?per_page=100"), "releases").All()
+}
+
+// DraftRelease creates a draft release for tag with notes generated by GitHub
+// from the pull requests merged since previousTag (or since the start of
+// history when previousTag is empty).
+func (c *Client) DraftRelease(tag, previousTag string) (*Release, error) {
+	req := map[string]string{"tag_name": tag}
+	if previousTag != "" {
+		req["previous_tag_name"] = previousTag
+	}
+	var notes generatedNotes
+	if err := c.post(c.endpoint("releases/generate-notes"), req, &notes); err != nil {
+		return nil, fmt.Errorf("failed to generate release notes: %w", err)
+	}
+
+	var release Release
+	err := c.post(c.endpoint("releases"), map[string]any{
+		"tag_name": tag,
+		"name":     notes.Name,
+		"body":     notes.Body,
+		"draft":    true,
+	}, &release)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create release: %w", err)
+	}
+	return &release, nil
+}
+
+func (c *Client) post(path string, body, resp any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return c.rest.Post(path, bytes.NewReader(data), resp)
+}
@@ -0,0 +1,154 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/zalando/go-keyring"
+)
+
+// keyringService is the service hit's secrets are stored under.
+const keyringService = "hit"
+
+// ConfigDir is where hit keeps its settings: $XDG_CONFIG_HOME/hit, or the
+// platform config directory when XDG_CONFIG_HOME is unset.
+func ConfigDir() string {
+	base := os.Getenv("XDG_CONFIG_HOME")
+	if base == "" {
+		var err error
+		if base, err = os.UserConfigDir(); err != nil {
+			return ""
+		}
+	}
+	return filepath.Join(base, "hit")
+}
+
+func tokensFile() string {
+	return filepath.Join(ConfigDir(), "tokens.json")
+}
+
+// StoreToken saves the token of login on host in the system keyring (the
+// Secret Service over D-Bus on Linux), or, when no keyring is available, in
+// a file only the user can read. It returns "keyring" or the path of the
+// file. An empty login saves a token whose user is unknown.
+func StoreToken(host, login, token string) (string, error) {
+	if err := keyring.Set(keyringService, tokenKey(host, login), token); err == nil {
+		// a keyring token supersedes one left in the file
+		removeFileToken(tokenKey(host, login))
+		return "keyring", nil
+	}
+	if err := storeFileToken(tokenKey(host, login), token); err != nil {
+		return "", err
+	}
+	return tokensFile(), nil
+}
+
+// StoredToken returns the token hit saved for login on host and where it was
+// found. An empty login finds the token saved without one.
+func StoredToken(host, login string) (string, string) {
+	if token, err := keyring.Get(keyringService, tokenKey(host, login)); err == nil && token != "" {
+		return token, "keyring"
+	}
+	tokens, _ := readTokenFile()
+	if token := tokens[tokenKey(host, login)]; token != "" {
+		return token, tokensFile()
+	}
+	return "", ""
+}
+
+// DeleteToken forgets the token hit saved for login on host. An empty login
+// forgets the token saved without one.
+func DeleteToken(host, login string) error {
+	err := keyring.Delete(keyringService, tokenKey(host, login))
+	if errors.Is(err, keyring.ErrNotFound) {
+		err = nil
+	} else if err != nil {
+		// without a reachable keyring there is no token in it to forget
+		if _, getErr := keyring.Get(keyringService, tokenKey(host, login)); getErr != nil {
+			err = nil
+		}
+	}
+	return errors.Join(err, removeFileToken(tokenKey(host, login)))
+}
+
+// tokenKey names a token in the keyring and the file: the host, prefixed by
+// the login when it is known.
+func tokenKey(host, login string) string {
+	if login == "" {
+		return host
+	}
+	return login + "@" + host
+}
+
+func readTokenFile() (map[string]string, error) {
+	data, err := os.ReadFile(tokensFile())
+	if err != nil {
+		return nil, err
+	}
+	tokens := map[string]string{}
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func storeFileToken(key, token string) error {
+	tokens, err := readTokenFile()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if tokens == nil {
+		tokens = map[string]string{}
+	}
+	tokens[key] = token
+	return writeTokenFile(tokens)
+}
+
+func removeFileToken(key string) error {
+	tokens, err := readTokenFile()
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if _, ok := tokens[key]; !ok {
+		return nil
+	}
+	delete(tokens, key)
+	return writeTokenFile(tokens)
+}
+
+// writeTokenFile replaces the file atomically, readable by the user only.
+func writeTokenFile(tokens map[string]string) error {
+	data, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return err
+	}
+	dir := ConfigDir()
+	if dir == "" {
+		return errors.New("no config directory")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "tokens-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), tokensFile())
+}
@@ -59,6 +59,10 @@ type OrgRepo struct {
 	Private     bool      `json:"private"`
 	Archived    bool      `json:"archived"`
 	UpdatedAt   time.Time `json:"updated_at"`
+	// DefaultBranch and CIStatus (the default branch's check rollup state,
+	// such as SUCCESS or FAILURE) are only filled in from GraphQL.
+	DefaultBranch string `json:"-"`
+	CIStatus      string `json:"-"`
 }
 
 type PR struct {
@@ -70,3 +74,39 @@ type PR struct {
 		Ref string `json:"ref"`
 	} `json:"head"`
 }
+
+type Release struct {
+	ID          int64     `json:"id"`
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Body        string    `json:"body"`
+	Draft       bool      `json:"draft"`
+	Prerelease  bool      `json:"prerelease"`
+	CreatedAt   time.Time `json:"created_at"`
+	PublishedAt time.Time `json:"published_at"`
+	HTMLURL     string    `json:"html_url"`
+	Assets      []Asset   `json:"assets"`
+}
+
+type Asset struct {
+	Name          string `json:"name"`
+	Size          int64  `json:"size"`
+	DownloadCount int    `json:"download_count"`
+}
+
+// PullRequest is an open pull request with its review and check rollups.
+// ReviewDecision is APPROVED, CHANGES_REQUESTED, REVIEW_REQUIRED or empty;
+// Checks is the head commit's rollup state, such as SUCCESS or PENDING.
+type PullRequest struct {
+	Number           int
+	Title            string
+	URL              string
+	Author           string
+	HeadRef          string
+	BaseRef          string
+	Draft            bool
+	UpdatedAt        time.Time
+	ReviewDecision   string
+	ReviewsRequested int
+	Checks           string
+}
@@ -0,0 +1,16 @@
+package styles
+
+// CheckBadge renders a commit's status check rollup state as reported by
+// GraphQL (SUCCESS, FAILURE, ERROR, PENDING or EXPECTED); it is empty when
+// the commit has no checks.
+func CheckBadge(state string) string {
+	switch state {
+	case "SUCCESS":
+		return BadgeSuccess.Render(IconCheck)
+	case "FAILURE", "ERROR":
+		return BadgeFailure.Render(IconCross)
+	case "PENDING", "EXPECTED":
+		return BadgePending.Render(IconPending)
+	}
+	return ""
+}
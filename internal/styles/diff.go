@@ -0,0 +1,37 @@
+package styles
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	diffAddStyle  = lipgloss.NewStyle().Foreground(ColorSuccess)
+	diffDelStyle  = lipgloss.NewStyle().Foreground(ColorError)
+	diffFileStyle = lipgloss.NewStyle().Foreground(ColorText).Bold(true)
+)
+
+// DiffLine colors a single line of unified diff output.
+func DiffLine(line string) string {
+	switch {
+	case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"), strings.HasPrefix(line, "diff --git"):
+		return diffFileStyle.Render(line)
+	case strings.HasPrefix(line, "@@"):
+		return HighlightStyle.Render(line)
+	case strings.HasPrefix(line, "+"):
+		return diffAddStyle.Render(line)
+	case strings.HasPrefix(line, "-"):
+		return diffDelStyle.Render(line)
+	}
+	return line
+}
+
+// Diff colors a whole unified diff.
+func Diff(diff string) string {
+	lines := strings.Split(diff, "\n")
+	for i, l := range lines {
+		lines[i] = DiffLine(l)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -18,4 +18,11 @@ const (
 	IconRefresh  = "\uf021" //
 	IconOrg      = "\uf0c0" //
 	IconHistory  = "\uf1da" //
+	IconEdit     = "\uf044" //
+	IconStash    = "\uf01c" //
+	IconTree     = "\uf1bb" //
+	IconTag      = "\uf02b" //
+	IconRepo     = "\uf401" //
+	IconUser     = "\uf007" //
+	IconStar     = "\uf005" //
 )
@@ -9,13 +9,14 @@ import (
 )
 
 type branchItem struct {
-	branch git.Branch
+	branch  git.Branch
+	pending bool // ahead/behind counts not computed yet
 }
 
 func (b branchItem) Title() string {
 	prefix := "  "
 	if b.branch.IsCurrent {
-		prefix = styles.HighlightStyle.Render(styles.IconBranch+" ")
+		prefix = styles.HighlightStyle.Render(styles.IconBranch + " ")
 	}
 	return prefix + b.branch.Name
 }
@@ -25,9 +26,9 @@ func (b branchItem) Description() string {
 		fmt.Sprintf("%s %s", styles.SubtitleStyle.Render(b.branch.Hash), b.branch.Subject),
 	}
 
-	parts = append(parts, remoteStatus(b.branch))
+	parts = append(parts, remoteStatus(b.branch, b.pending))
 
-	if ds := defaultStatus(b.branch); ds != "" {
+	if ds := defaultStatus(b.branch, b.pending); ds != "" {
 		parts = append(parts, ds)
 	}
 
@@ -38,19 +39,25 @@ func (b branchItem) FilterValue() string {
 	return b.branch.Name
 }
 
-func remoteStatus(b git.Branch) string {
+func remoteStatus(b git.Branch, pending bool) string {
 	if !b.HasRemote {
 		return styles.SubtitleStyle.Render(styles.IconLocal + " local")
 	}
-	return styles.BadgeSuccess.Render(styles.IconCloud + " " + formatAheadBehind(b.RemoteAhead, b.RemoteBehind))
+	if pending {
+		return styles.SubtitleStyle.Render(styles.IconCloud + " " + b.Upstream + " " + styles.IconPending)
+	}
+	return styles.BadgeSuccess.Render(styles.IconCloud + " " + b.Upstream + " " + formatAheadBehind(b.RemoteAhead, b.RemoteBehind))
 }
 
-func defaultStatus(b git.Branch) string {
+func defaultStatus(b git.Branch, pending bool) string {
 	if b.IsDefault || b.DefaultBranch == "" {
 		return ""
 	}
+	if pending {
+		return styles.SubtitleStyle.Render(styles.IconPending + " " + b.DefaultRef)
+	}
 	ab := formatAheadBehind(b.DefaultAhead, b.DefaultBehind)
-	return styles.SubtitleStyle.Render(ab + " " + b.DefaultBranch)
+	return styles.SubtitleStyle.Render(ab + " " + b.DefaultRef)
 }
 
 func formatAheadBehind(ahead, behind int) string {
@@ -1,23 +1,48 @@
 package branches
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/elisa-content-delivery/hit/internal/git"
 	"github.com/elisa-content-delivery/hit/internal/styles"
+	"github.com/elisa-content-delivery/hit/internal/ui/commits"
+	"github.com/elisa-content-delivery/hit/internal/ui/compare"
+	"github.com/elisa-content-delivery/hit/internal/ui/conflict"
+	"github.com/elisa-content-delivery/hit/internal/ui/rebase"
 	"github.com/elisa-content-delivery/hit/internal/ui/reflog"
+	"github.com/elisa-content-delivery/hit/internal/ui/submodules"
 )
 
+// checkoutDoneMsg reports a checkout; stashed is set when local changes were
+// stashed before it, even if the checkout then failed.
 type checkoutDoneMsg struct {
-	branch string
-	err    error
+	branch  string
+	stashed bool
+	err     error
 }
 
-type branchesLoadedMsg struct {
-	branches []git.Branch
-	err      error
+// LoadedMsg carries a fresh branch list. Like AheadBehindMsg it reaches the
+// model whatever view is shown, so a refresh asked for from elsewhere lands.
+type LoadedMsg struct {
+	branches   []git.Branch
+	head       git.HeadState
+	nearest    string // closest branch or tag to a detached HEAD
+	op         git.Operation
+	submodules bool // a .gitmodules file exists
+	err        error
+}
+
+// SubmodulesCheckedMsg carries how many submodules need attention. Checking
+// them runs git in every submodule, so it follows the branch list instead of
+// holding it up; the app routes it here whatever view is shown.
+type SubmodulesCheckedMsg struct {
+	stale int
 }
 
 type branchCreatedMsg struct {
@@ -27,29 +52,53 @@ type branchCreatedMsg struct {
 
 type pushDoneMsg struct {
 	branch string
+	remote string
 	err    error
 }
 
+// AheadBehindMsg delivers one branch's sync counts from the stream started
+// after the branches are listed. The app routes it to the model whatever view
+// is shown, so the stream keeps draining in the background.
+type AheadBehindMsg struct {
+	result git.AheadBehindResult
+	counts <-chan git.AheadBehindResult
+}
+
+type integrateDoneMsg struct {
+	op      git.Operation
+	branch  string
+	onto    string
+	stopped bool
+	err     error
+}
+
 type branchRenamedMsg struct {
-	oldName     string
-	newName     string
+	oldName       string
+	newName       string
 	renamedRemote bool
-	err         error
+	err           error
 }
 
 type Model struct {
-	repo              *git.Repo
-	list              list.Model
-	nameInput         textinput.Model
-	creating          bool
-	renaming          bool
-	renamingFrom      string
-	confirmRemote     bool
-	pendingOldName    string
-	pendingNewName    string
-	width             int
-	height            int
-	status            string
+	repo            *git.Repo
+	list            list.Model
+	counts          <-chan git.AheadBehindResult
+	head            git.HeadState
+	nearest         string
+	staleSubmodules int
+	nameInput       textinput.Model
+	creating        bool
+	renaming        bool
+	renamingFrom    string
+	confirmRemote   bool
+	pendingOldName  string
+	pendingNewName  string
+	width           int
+	height          int
+	status          string
+	inProgress      git.Operation
+	confirmStash    bool
+	pendingCheckout string
 }
 
 func New(repo *git.Repo) Model {
@@ -67,13 +116,17 @@ func New(repo *git.Repo) Model {
 }
 
 func (m Model) IsInputActive() bool {
-	return m.creating || m.renaming || m.confirmRemote
+	return m.creating || m.renaming || m.confirmRemote || m.confirmStash || m.list.FilterState() == list.Filtering
 }
 
 func (m Model) IsConfirming() bool {
 	return m.confirmRemote
 }
 
+func (m Model) IsConfirmingStash() bool {
+	return m.confirmStash
+}
+
 func (m Model) Init() tea.Cmd {
 	return m.loadBranches
 }
@@ -86,25 +139,78 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		m.list.SetSize(msg.Width, msg.Height-4)
 		return m, nil
 
-	case branchesLoadedMsg:
+	case LoadedMsg:
 		if msg.err != nil {
 			m.status = styles.ErrorLineStyle.Render("Error: ") + styles.SubtitleStyle.Render(msg.err.Error())
 			return m, nil
 		}
+		// Keep the previous counts on screen until fresh ones arrive
+		previous := make(map[string]git.Branch)
+		for _, item := range m.list.Items() {
+			if bi, ok := item.(branchItem); ok && !bi.pending {
+				previous[bi.branch.Name] = bi.branch
+			}
+		}
 		items := make([]list.Item, len(msg.branches))
 		for i, b := range msg.branches {
-			items[i] = branchItem{branch: b}
+			old, ok := previous[b.Name]
+			if ok {
+				b.RemoteAhead, b.RemoteBehind = old.RemoteAhead, old.RemoteBehind
+				b.DefaultAhead, b.DefaultBehind = old.DefaultAhead, old.DefaultBehind
+			}
+			items[i] = branchItem{branch: b, pending: !ok}
 		}
 		m.list.SetItems(items)
+		m.head = msg.head
+		m.nearest = msg.nearest
+		m.inProgress = msg.op
 		m.status = ""
+		m.counts = m.repo.StreamAheadBehind(msg.branches)
+		if !msg.submodules {
+			m.staleSubmodules = 0
+			return m, waitForCounts(m.counts)
+		}
+		return m, tea.Batch(waitForCounts(m.counts), m.checkSubmodules)
+
+	case SubmodulesCheckedMsg:
+		m.staleSubmodules = msg.stale
 		return m, nil
 
+	case AheadBehindMsg:
+		if msg.counts != m.counts {
+			return m, nil
+		}
+		for i, item := range m.list.Items() {
+			bi, ok := item.(branchItem)
+			if !ok || bi.branch.Name != msg.result.Branch {
+				continue
+			}
+			bi.branch.RemoteAhead, bi.branch.RemoteBehind = msg.result.RemoteAhead, msg.result.RemoteBehind
+			bi.branch.DefaultAhead, bi.branch.DefaultBehind = msg.result.DefaultAhead, msg.result.DefaultBehind
+			bi.pending = false
+			cmd := m.list.SetItem(i, bi)
+			return m, tea.Batch(cmd, waitForCounts(m.counts))
+		}
+		return m, waitForCounts(m.counts)
+
 	case checkoutDoneMsg:
 		if msg.err != nil {
 			m.status = styles.ErrorLineStyle.Render("Checkout failed: ") + styles.SubtitleStyle.Render(msg.err.Error())
+			var dirty *git.DirtyWorktreeError
+			if errors.As(msg.err, &dirty) && !msg.stashed {
+				m.confirmStash = true
+				m.pendingCheckout = msg.branch
+			}
+			if msg.stashed {
+				m.status += styles.SubtitleStyle.Render(" · your changes were stashed as stash@{0}")
+				return m, emitRefreshReflog
+			}
 			return m, nil
 		}
 		m.status = styles.BadgeSuccess.Render("Switched to ") + styles.HighlightStyle.Render(msg.branch)
+		if msg.stashed {
+			m.status += styles.SubtitleStyle.Render(" · local changes stashed")
+		}
 		return m, tea.Batch(m.loadBranches, emitRefreshReflog)
 
 	case branchCreatedMsg:
@@ -120,7 +226,26 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			m.status = styles.ErrorLineStyle.Render("Push failed: ") + styles.SubtitleStyle.Render(msg.err.Error())
 			return m, nil
 		}
-		m.status = styles.BadgeSuccess.Render("Pushed ") + styles.HighlightStyle.Render(msg.branch) + styles.BadgeSuccess.Render(" to origin")
+		m.status = styles.BadgeSuccess.Render("Pushed ") + styles.HighlightStyle.Render(msg.branch) + styles.BadgeSuccess.Render(" to "+msg.remote)
+		return m, tea.Batch(m.loadBranches, emitRefreshReflog)
+
+	case integrateDoneMsg:
+		if msg.stopped {
+			m.status = styles.BadgePending.Render(msg.op.String()+" stopped on conflicts in ") + styles.HighlightStyle.Render(msg.branch)
+			return m, tea.Batch(m.loadBranches, emitRefreshReflog, emitOpenConflicts)
+		}
+		if msg.err != nil {
+			m.status = styles.ErrorLineStyle.Render(capitalize(msg.op.String())+" failed: ") + styles.SubtitleStyle.Render(msg.err.Error())
+			return m, nil
+		}
+		verb := "Rebased "
+		if msg.op == git.OpMerge {
+			verb = "Merged " + msg.onto + " into "
+		}
+		m.status = styles.BadgeSuccess.Render(verb) + styles.HighlightStyle.Render(msg.branch)
+		if msg.op == git.OpRebase {
+			m.status += styles.BadgeSuccess.Render(" onto " + msg.onto)
+		}
 		return m, tea.Batch(m.loadBranches, emitRefreshReflog)
 
 	case branchRenamedMsg:
@@ -139,6 +264,9 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		if m.confirmRemote {
 			return m.handleConfirmRemote(msg)
 		}
+		if m.confirmStash {
+			return m.handleConfirmStash(msg)
+		}
 		if m.creating {
 			return m.handleCreateInput(msg)
 		}
@@ -169,6 +297,59 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			m.status = styles.BadgePending.Render("Refreshing...")
 			return m, m.loadBranches
 
+		case "b", "m":
+			selected, ok := m.list.SelectedItem().(branchItem)
+			if !ok {
+				return m, nil
+			}
+			if m.inProgress != git.OpNone {
+				return m, emitOpenConflicts
+			}
+			onto := m.repo.DefaultBranchRef()
+			if onto == "" || selected.branch.IsDefault {
+				m.status = styles.BadgeNeutral.Render("No default branch to integrate")
+				return m, nil
+			}
+			op := git.OpRebase
+			if msg.String() == "m" {
+				op = git.OpMerge
+			}
+			m.status = styles.BadgePending.Render(capitalize(op.String())+" ") + styles.HighlightStyle.Render(selected.branch.Name) + styles.BadgePending.Render(" with "+onto+"...")
+			return m, m.integrate(op, selected.branch.Name, onto)
+
+		case "x":
+			if m.inProgress != git.OpNone {
+				return m, emitOpenConflicts
+			}
+
+		case "i":
+			if m.inProgress != git.OpNone {
+				return m, emitOpenConflicts
+			}
+			return m, func() tea.Msg { return rebase.OpenMsg{} }
+
+		case "l":
+			selected, ok := m.list.SelectedItem().(branchItem)
+			if !ok {
+				return m, nil
+			}
+			return m, func() tea.Msg { return commits.OpenMsg{Branch: selected.branch.Name} }
+
+		case "S":
+			return m, func() tea.Msg { return submodules.OpenMsg{} }
+
+		case "c":
+			selected, ok := m.list.SelectedItem().(branchItem)
+			if !ok {
+				return m, nil
+			}
+			base := m.repo.DefaultBranchRef()
+			if base == "" {
+				base = "HEAD"
+			}
+			head := selected.branch.Name
+			return m, func() tea.Msg { return compare.OpenMsg{Base: base, Head: head} }
+
 		case "a":
 			m.creating = true
 			m.nameInput.SetValue("")
@@ -208,6 +389,30 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 func (m Model) View() string {
 	content := m.list.View()
+	switch {
+	case m.head.Unborn:
+		content += "\n" + lipgloss.NewStyle().MarginLeft(2).Render(
+			styles.BadgePending.Render("No commits yet on "+m.head.Branch)+styles.SubtitleStyle.Render(" · stage files in Status and commit"))
+	case m.head.Detached:
+		at := styles.HighlightStyle.Render(m.head.Hash)
+		if m.nearest != "" {
+			at += styles.SubtitleStyle.Render(" (" + m.nearest + ")")
+		}
+		content += "\n" + lipgloss.NewStyle().MarginLeft(2).Render(
+			styles.BadgePending.Render("HEAD detached at ")+at+styles.SubtitleStyle.Render(" · enter checks out a branch"))
+	}
+	if m.staleSubmodules > 0 {
+		noun := "submodules need"
+		if m.staleSubmodules == 1 {
+			noun = "submodule needs"
+		}
+		content += "\n" + lipgloss.NewStyle().MarginLeft(2).Render(
+			styles.BadgePending.Render(fmt.Sprintf("%d %s attention", m.staleSubmodules, noun))+styles.SubtitleStyle.Render(" · press S to review"))
+	}
+	if m.inProgress != git.OpNone {
+		content += "\n" + lipgloss.NewStyle().MarginLeft(2).Render(
+			styles.BadgePending.Render(capitalize(m.inProgress.String())+" in progress")+styles.SubtitleStyle.Render(" · press x to resolve"))
+	}
 	if m.creating || m.renaming {
 		content += "\n" + lipgloss.NewStyle().MarginLeft(2).Render(m.nameInput.View())
 	}
@@ -217,12 +422,34 @@ func (m Model) View() string {
 	if m.confirmRemote {
 		content = m.renderConfirmOverlay(content)
 	}
+	if m.confirmStash {
+		content = m.renderStashOverlay()
+	}
 	return content
 }
 
+func (m Model) renderStashOverlay() string {
+	title := styles.TitleStyle.Render("Stash local changes?")
+	desc := styles.SubtitleStyle.Render("Checking out " + m.pendingCheckout + " would overwrite uncommitted changes.\nStash them first? Restore them later from the Stash view.")
+	hint := styles.HighlightStyle.Render("y") + styles.SubtitleStyle.Render(": stash and checkout") +
+		"\n" + styles.HighlightStyle.Render("n") + styles.SubtitleStyle.Render(": cancel")
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.ColorWarning).
+		Padding(1, 2).
+		Width(56).
+		Render(title + "\n\n" + desc + "\n\n" + hint)
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box,
+		lipgloss.WithWhitespaceChars(" "),
+		lipgloss.WithWhitespaceForeground(lipgloss.Color("0")),
+	)
+}
+
 func (m Model) renderConfirmOverlay(_ string) string {
 	title := styles.TitleStyle.Render("Rename remote branch?")
-	desc := styles.SubtitleStyle.Render(m.pendingOldName+" exists on remote.\nThis will delete the old remote branch and force push the new name.")
+	desc := styles.SubtitleStyle.Render(m.pendingOldName + " exists on remote.\nThis will delete the old remote branch and force push the new name.")
 	hint := styles.HighlightStyle.Render("y") + styles.SubtitleStyle.Render(": yes, rename remote too") +
 		"\n" + styles.HighlightStyle.Render("n") + styles.SubtitleStyle.Render(": no, local only") +
 		"\n" + styles.HighlightStyle.Render("esc") + styles.SubtitleStyle.Render(": cancel")
@@ -306,9 +533,53 @@ func (m Model) handleConfirmRemote(msg tea.KeyMsg) (Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m Model) handleConfirmStash(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "y":
+		m.confirmStash = false
+		m.status = styles.BadgePending.Render("Stashing and checking out ") + styles.HighlightStyle.Render(m.pendingCheckout) + styles.BadgePending.Render("...")
+		return m, m.stashAndCheckout(m.pendingCheckout)
+	case "n", "esc":
+		m.confirmStash = false
+		return m, nil
+	}
+	return m, nil
+}
+
 func (m Model) loadBranches() tea.Msg {
 	branches, err := m.repo.ListBranches()
-	return branchesLoadedMsg{branches: branches, err: err}
+	msg := LoadedMsg{branches: branches, head: m.repo.Head(), op: m.repo.InProgress(), submodules: m.repo.HasSubmodules(), err: err}
+	if msg.head.Detached {
+		msg.nearest = m.repo.NearestRef()
+	}
+	return msg
+}
+
+func (m Model) checkSubmodules() tea.Msg {
+	subs, err := m.repo.Submodules()
+	if err != nil {
+		return nil
+	}
+	stale := 0
+	for _, s := range subs {
+		if s.NeedsAttention() {
+			stale++
+		}
+	}
+	return SubmodulesCheckedMsg{stale: stale}
+}
+
+func (m Model) integrate(op git.Operation, branch, onto string) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		if op == git.OpMerge {
+			err = m.repo.Merge(branch, onto)
+		} else {
+			err = m.repo.Rebase(branch, onto)
+		}
+		stopped := err != nil && m.repo.InProgress() != git.OpNone
+		return integrateDoneMsg{op: op, branch: branch, onto: onto, stopped: stopped, err: err}
+	}
 }
 
 func (m Model) checkout(name string) tea.Cmd {
@@ -318,6 +589,28 @@ func (m Model) checkout(name string) tea.Cmd {
 	}
 }
 
+func (m Model) stashAndCheckout(name string) tea.Cmd {
+	return func() tea.Msg {
+		if err := m.repo.StashPush("hit: auto-stash before checkout of "+name, false); err != nil {
+			return checkoutDoneMsg{branch: name, err: fmt.Errorf("could not stash local changes: %w", err)}
+		}
+		err := m.repo.Checkout(name)
+		return checkoutDoneMsg{branch: name, stashed: true, err: err}
+	}
+}
+
+// waitForCounts delivers the next ahead/behind result, or nothing once the
+// stream is exhausted.
+func waitForCounts(counts <-chan git.AheadBehindResult) tea.Cmd {
+	return func() tea.Msg {
+		result, ok := <-counts
+		if !ok {
+			return nil
+		}
+		return AheadBehindMsg{result: result, counts: counts}
+	}
+}
+
 func (m Model) createBranch(name string) tea.Cmd {
 	return func() tea.Msg {
 		err := m.repo.CreateBranch(name)
@@ -327,8 +620,8 @@ func (m Model) createBranch(name string) tea.Cmd {
 
 func (m Model) pushBranch(branch string) tea.Cmd {
 	return func() tea.Msg {
-		err := m.repo.PushBranch(branch)
-		return pushDoneMsg{branch: branch, err: err}
+		remote, err := m.repo.PushBranch(branch)
+		return pushDoneMsg{branch: branch, remote: remote, err: err}
 	}
 }
 
@@ -336,6 +629,17 @@ func emitRefreshReflog() tea.Msg {
 	return reflog.RefreshReflogMsg{}
 }
 
+func emitOpenConflicts() tea.Msg {
+	return conflict.OpenMsg{}
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
 func (m Model) renameBranch(oldName, newName string, renameRemote bool) tea.Cmd {
 	return func() tea.Msg {
 		err := m.repo.RenameBranch(oldName, newName)
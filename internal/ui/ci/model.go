@@ -9,6 +9,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	gh "github.com/elisa-content-delivery/hit/internal/github"
 	"github.com/elisa-content-delivery/hit/internal/styles"
+	"github.com/elisa-content-delivery/hit/internal/ui/paging"
 )
 
 type pane int
@@ -20,11 +21,6 @@ const (
 	paneLogs
 )
 
-type runsLoadedMsg struct {
-	runs []gh.WorkflowRun
-	err  error
-}
-
 type jobsLoadedMsg struct {
 	jobs []gh.Job
 	err  error
@@ -75,6 +71,9 @@ type Model struct {
 	branch      string
 	currentPane pane
 	runsList    list.Model
+	runsPager   *gh.Pager[gh.WorkflowRun]
+	loadingMore bool
+	refreshing  bool
 	jobsList    list.Model
 	stepsList   list.Model
 	logView     LogView
@@ -88,6 +87,8 @@ type Model struct {
 	status      string
 }
 
+// New lists the workflow runs of branch. client may be nil before
+// authentication.
 func New(client *gh.Client, branch string) Model {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
@@ -102,7 +103,7 @@ func New(client *gh.Client, branch string) Model {
 		return l
 	}
 
-	return Model{
+	m := Model{
 		client:      client,
 		branch:      branch,
 		currentPane: paneRuns,
@@ -112,10 +113,17 @@ func New(client *gh.Client, branch string) Model {
 		logView:     NewLogView(),
 		spinner:     s,
 	}
+	if client != nil {
+		m.runsPager = client.Runs(branch, 20)
+	}
+	return m
 }
 
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(m.spinner.Tick, m.loadRuns)
+	if m.client == nil {
+		return nil
+	}
+	return tea.Batch(m.spinner.Tick, paging.LoadCached(m.runsPager), paging.Load(m.runsPager, false))
 }
 
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
@@ -136,17 +144,22 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		m.spinner, cmd = m.spinner.Update(msg)
 		return m, cmd
 
-	case runsLoadedMsg:
+	case paging.Msg[gh.WorkflowRun]:
+		if msg.Pager != m.runsPager || msg.Cached && !m.loading {
+			return m, nil
+		}
 		m.loading = false
-		if msg.err != nil {
-			m.status = fmt.Sprintf("Error: %s", msg.err)
+		m.loadingMore = false
+		m.refreshing = msg.Cached
+		if msg.Err != nil {
+			m.status = fmt.Sprintf("Error: %s", msg.Err)
 			return m, nil
 		}
-		items := make([]list.Item, len(msg.runs))
-		for i, r := range msg.runs {
-			items[i] = runItem{run: r}
+		m.status = ""
+		if msg.Cached {
+			m.status = "Refreshing..."
 		}
-		m.runsList.SetItems(items)
+		m.runsList.SetItems(msg.ListItems(m.runsList.Items(), func(r gh.WorkflowRun) list.Item { return runItem{run: r} }))
 		return m, nil
 
 	case jobsLoadedMsg:
@@ -177,6 +190,9 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		if m.client == nil {
+			return m, nil
+		}
 		switch msg.String() {
 		case "esc":
 			return m.goBack()
@@ -187,8 +203,11 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		case "r":
 			if m.currentPane == paneRuns {
 				m.loading = true
+				m.loadingMore = false
+				m.refreshing = false
 				m.status = ""
-				return m, tea.Batch(m.spinner.Tick, m.loadRuns)
+				m.runsPager = m.client.Runs(m.branch, 20)
+				return m, tea.Batch(m.spinner.Tick, paging.Load(m.runsPager, false))
 			}
 		}
 	}
@@ -204,10 +223,29 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	case paneLogs:
 		m.logView, cmd = m.logView.Update(msg)
 	}
-	return m, cmd
+	return m, tea.Batch(cmd, m.loadMoreRuns())
+}
+
+// loadMoreRuns fetches the next page of runs once the selection reaches the
+// last loaded run.
+func (m *Model) loadMoreRuns() tea.Cmd {
+	if m.currentPane != paneRuns || m.runsPager == nil || m.loading || m.loadingMore || m.refreshing || !m.runsPager.HasNext() {
+		return nil
+	}
+	n := len(m.runsList.Items())
+	if n == 0 || m.runsList.Index() != n-1 {
+		return nil
+	}
+	m.loadingMore = true
+	m.status = "Loading more runs..."
+	return paging.Load(m.runsPager, true)
 }
 
 func (m Model) View() string {
+	if m.client == nil {
+		return styles.TitleStyle.Render("Workflow Runs") + "\n\n" +
+			styles.SubtitleStyle.Render("  Sign in to GitHub to list workflow runs")
+	}
 	if m.loading {
 		return m.spinner.View() + " Loading..."
 	}
@@ -304,11 +342,6 @@ func (m Model) drillDown() (Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m Model) loadRuns() tea.Msg {
-	runs, err := m.client.GetRuns(m.branch, 20)
-	return runsLoadedMsg{runs: runs, err: err}
-}
-
 func (m Model) loadJobs(runID int64) tea.Cmd {
 	return func() tea.Msg {
 		jobs, err := m.client.GetJobs(runID)
@@ -0,0 +1,389 @@
+package commit
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/elisa-content-delivery/hit/internal/git"
+	"github.com/elisa-content-delivery/hit/internal/styles"
+	"github.com/elisa-content-delivery/hit/internal/ui/reflog"
+)
+
+const (
+	subjectSoftLimit = 50
+	subjectHardLimit = 72
+)
+
+var commitTypes = []string{"", "feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore", "revert"}
+
+// OpenMsg asks the app to show the commit composer.
+type OpenMsg struct{}
+
+// DoneMsg is sent when the composer closes, after a commit or on cancel.
+type DoneMsg struct {
+	Committed bool
+}
+
+type field int
+
+const (
+	fieldType field = iota
+	fieldScope
+	fieldMessage
+	fieldAmend
+	fieldSignOff
+	fieldSign
+	fieldCount
+)
+
+type commitDoneMsg struct {
+	err error
+}
+
+type lastMessageMsg struct {
+	message string
+}
+
+type editorClosedMsg struct {
+	path string
+	err  error
+}
+
+type Model struct {
+	repo     *git.Repo
+	focus    field
+	typeIdx  int
+	breaking bool
+	scope    textinput.Model
+	message  textarea.Model
+	amend    bool
+	signOff  bool
+	sign     bool
+	running  bool
+	width    int
+	height   int
+	status   string
+}
+
+func New(repo *git.Repo) Model {
+	scope := textinput.New()
+	scope.Prompt = ""
+	scope.Placeholder = "scope"
+	scope.CharLimit = 32
+	scope.Width = 20
+
+	ta := textarea.New()
+	ta.Placeholder = "Subject line\n\nBody"
+	ta.ShowLineNumbers = false
+	ta.CharLimit = 0
+
+	m := Model{
+		repo:    repo,
+		focus:   fieldMessage,
+		scope:   scope,
+		message: ta,
+		sign:    repo.SigningEnabled(),
+	}
+	m.message.Focus()
+	return m
+}
+
+func (m Model) Init() tea.Cmd {
+	return textarea.Blink
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.message.SetWidth(max(msg.Width-4, 20))
+		m.message.SetHeight(max(msg.Height-12, 3))
+		return m, nil
+
+	case commitDoneMsg:
+		m.running = false
+		if msg.err != nil {
+			m.status = styles.ErrorLineStyle.Render("Commit failed: ") + styles.SubtitleStyle.Render(msg.err.Error())
+			return m, nil
+		}
+		return m, tea.Batch(emitRefreshReflog, func() tea.Msg { return DoneMsg{Committed: true} })
+
+	case lastMessageMsg:
+		if strings.TrimSpace(m.message.Value()) == "" {
+			m.message.SetValue(msg.message)
+		}
+		return m, nil
+
+	case editorClosedMsg:
+		if msg.err != nil {
+			m.status = styles.ErrorLineStyle.Render("Editor failed: ") + styles.SubtitleStyle.Render(msg.err.Error())
+			return m, nil
+		}
+		data, err := os.ReadFile(msg.path)
+		if err != nil {
+			m.status = styles.ErrorLineStyle.Render("Editor failed: ") + styles.SubtitleStyle.Render(err.Error())
+			return m, nil
+		}
+		m.message.SetValue(stripComments(string(data)))
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.running {
+			return m, nil
+		}
+		switch msg.String() {
+		case "esc":
+			return m, func() tea.Msg { return DoneMsg{} }
+		case "ctrl+s":
+			return m.commit()
+		case "ctrl+o":
+			return m.openEditor()
+		case "tab":
+			return m.setFocus((m.focus + 1) % fieldCount)
+		case "shift+tab":
+			return m.setFocus((m.focus + fieldCount - 1) % fieldCount)
+		}
+
+		switch m.focus {
+		case fieldType:
+			switch msg.String() {
+			case "left", "h":
+				m.typeIdx = (m.typeIdx + len(commitTypes) - 1) % len(commitTypes)
+			case "right", "l", " ":
+				m.typeIdx = (m.typeIdx + 1) % len(commitTypes)
+			case "!":
+				m.breaking = !m.breaking
+			}
+			return m, nil
+		case fieldScope:
+			var cmd tea.Cmd
+			m.scope, cmd = m.scope.Update(msg)
+			return m, cmd
+		case fieldMessage:
+			var cmd tea.Cmd
+			m.message, cmd = m.message.Update(msg)
+			return m, cmd
+		default:
+			if msg.String() == " " || msg.String() == "enter" {
+				return m.toggle()
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	switch m.focus {
+	case fieldScope:
+		m.scope, cmd = m.scope.Update(msg)
+	case fieldMessage:
+		m.message, cmd = m.message.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m Model) setFocus(f field) (Model, tea.Cmd) {
+	m.focus = f
+	m.scope.Blur()
+	m.message.Blur()
+	switch f {
+	case fieldScope:
+		return m, m.scope.Focus()
+	case fieldMessage:
+		return m, m.message.Focus()
+	}
+	return m, nil
+}
+
+func (m Model) toggle() (Model, tea.Cmd) {
+	switch m.focus {
+	case fieldAmend:
+		m.amend = !m.amend
+		if m.amend && strings.TrimSpace(m.message.Value()) == "" {
+			return m, m.loadLastMessage
+		}
+	case fieldSignOff:
+		m.signOff = !m.signOff
+	case fieldSign:
+		m.sign = !m.sign
+	}
+	return m, nil
+}
+
+// fullMessage prefixes the subject with the conventional-commit header when
+// a type is picked and the subject doesn't already carry one.
+func (m Model) fullMessage() string {
+	msg := strings.TrimSpace(m.message.Value())
+	header := m.header()
+	if header == "" || strings.HasPrefix(msg, header) {
+		return msg
+	}
+	return header + msg
+}
+
+func (m Model) header() string {
+	t := commitTypes[m.typeIdx]
+	if t == "" {
+		return ""
+	}
+	if scope := strings.TrimSpace(m.scope.Value()); scope != "" {
+		t += "(" + scope + ")"
+	}
+	if m.breaking {
+		t += "!"
+	}
+	return t + ": "
+}
+
+func (m Model) commit() (Model, tea.Cmd) {
+	message := m.fullMessage()
+	if strings.TrimSpace(m.message.Value()) == "" {
+		m.status = styles.ErrorLineStyle.Render("Empty commit message")
+		return m, nil
+	}
+	cmd, err := m.repo.CommitCmd(git.CommitOptions{
+		Message: message,
+		Amend:   m.amend,
+		SignOff: m.signOff,
+		Sign:    m.sign,
+	})
+	if err != nil {
+		m.status = styles.ErrorLineStyle.Render("Commit failed: ") + styles.SubtitleStyle.Render(err.Error())
+		return m, nil
+	}
+	m.running = true
+	m.status = styles.BadgePending.Render("Committing...")
+
+	// Signing may need the terminal for a passphrase prompt
+	if m.sign {
+		return m, tea.ExecProcess(cmd, func(err error) tea.Msg {
+			return commitDoneMsg{err: err}
+		})
+	}
+	return m, func() tea.Msg {
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return commitDoneMsg{err: fmt.Errorf("%s", strings.TrimSpace(string(out)))}
+		}
+		return commitDoneMsg{}
+	}
+}
+
+func (m Model) openEditor() (Model, tea.Cmd) {
+	path, err := m.repo.CommitMessageFile()
+	if err == nil {
+		err = os.WriteFile(path, []byte(m.fullMessage()+"\n"), 0o644)
+	}
+	if err != nil {
+		m.status = styles.ErrorLineStyle.Render("Editor failed: ") + styles.SubtitleStyle.Render(err.Error())
+		return m, nil
+	}
+	return m, tea.ExecProcess(m.repo.EditorCmd(path), func(err error) tea.Msg {
+		return editorClosedMsg{path: path, err: err}
+	})
+}
+
+func (m Model) View() string {
+	title := styles.TitleStyle.Render("Commit")
+	if m.amend {
+		title += styles.BadgePending.Render("  (amend)")
+	}
+
+	typeLabel := commitTypes[m.typeIdx]
+	if typeLabel == "" {
+		typeLabel = "none"
+	}
+	if m.breaking {
+		typeLabel += "!"
+	}
+	typeRow := m.label(fieldType, "Type") + styles.HighlightStyle.Render("‹ "+typeLabel+" ›")
+	scopeRow := m.label(fieldScope, "Scope") + m.scope.View()
+
+	options := strings.Join([]string{
+		m.checkbox(fieldAmend, "amend", m.amend),
+		m.checkbox(fieldSignOff, "sign-off", m.signOff),
+		m.checkbox(fieldSign, "sign", m.sign),
+	}, "   ")
+
+	parts := []string{
+		title,
+		"",
+		typeRow,
+		scopeRow,
+		"",
+		m.message.View(),
+		m.ruler(),
+		"",
+		options,
+	}
+	if m.status != "" {
+		parts = append(parts, "", m.status)
+	}
+	return lipgloss.NewStyle().MarginLeft(2).Render(strings.Join(parts, "\n"))
+}
+
+func (m Model) label(f field, name string) string {
+	style := styles.SubtitleStyle
+	if m.focus == f {
+		style = styles.HighlightStyle
+	}
+	return style.Render(fmt.Sprintf("%-7s", name))
+}
+
+func (m Model) checkbox(f field, name string, on bool) string {
+	box := "[ ]"
+	if on {
+		box = "[x]"
+	}
+	style := styles.SubtitleStyle
+	if m.focus == f {
+		style = styles.HighlightStyle
+	}
+	return style.Render(box + " " + name)
+}
+
+// ruler shows the subject length against the 50/72 column conventions.
+func (m Model) ruler() string {
+	subject := strings.SplitN(m.fullMessage(), "\n", 2)[0]
+	n := len([]rune(subject))
+
+	style := styles.BadgeSuccess
+	switch {
+	case n > subjectHardLimit:
+		style = styles.BadgeFailure
+	case n > subjectSoftLimit:
+		style = styles.BadgePending
+	}
+
+	marks := strings.Repeat("─", subjectSoftLimit-1) + "┤" + strings.Repeat("─", subjectHardLimit-subjectSoftLimit-1) + "┤"
+	filled := min(n, len([]rune(marks)))
+	runes := []rune(marks)
+	bar := style.Render(string(runes[:filled])) + styles.SubtitleStyle.Render(string(runes[filled:]))
+	return bar + " " + style.Render(fmt.Sprintf("%d", n)) + styles.SubtitleStyle.Render(fmt.Sprintf("/%d", subjectSoftLimit))
+}
+
+func (m Model) loadLastMessage() tea.Msg {
+	msg, err := m.repo.LastCommitMessage()
+	if err != nil {
+		return nil
+	}
+	return lastMessageMsg{message: strings.TrimSpace(msg)}
+}
+
+func stripComments(s string) string {
+	var lines []string
+	for _, l := range strings.Split(s, "\n") {
+		if !strings.HasPrefix(l, "#") {
+			lines = append(lines, l)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func emitRefreshReflog() tea.Msg {
+	return reflog.RefreshReflogMsg{}
+}
@@ -0,0 +1,428 @@
+package commits
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/elisa-content-delivery/hit/internal/git"
+	"github.com/elisa-content-delivery/hit/internal/styles"
+	"github.com/elisa-content-delivery/hit/internal/ui/conflict"
+	"github.com/elisa-content-delivery/hit/internal/ui/reflog"
+)
+
+const pageSize = 200
+
+// OpenMsg asks the app to show the commit log of Branch.
+type OpenMsg struct {
+	Branch string
+}
+
+// CloseMsg returns to the view the log was opened from.
+type CloseMsg struct{}
+
+type logLoadedMsg struct {
+	lines []git.LogLine
+	limit int
+	err   error
+}
+
+type cherryPickDoneMsg struct {
+	count   int
+	target  string
+	stopped bool
+	err     error
+}
+
+type inputStep int
+
+const (
+	inputNone inputStep = iota
+	inputBranch
+	inputBase
+)
+
+type Model struct {
+	repo    *git.Repo
+	branch  string
+	base    string
+	compare bool
+	lines   []git.LogLine
+	cursor  int
+	limit   int
+	more    bool
+	loading bool
+	marked  map[string]bool
+	input   textinput.Model
+	step    inputStep
+	newName string
+	width   int
+	height  int
+	status  string
+}
+
+func New(repo *git.Repo, branch string) Model {
+	ti := textinput.New()
+	ti.CharLimit = 128
+
+	return Model{
+		repo:    repo,
+		branch:  branch,
+		base:    repo.DefaultBranchRef(),
+		limit:   pageSize,
+		loading: true,
+		marked:  make(map[string]bool),
+		input:   ti,
+	}
+}
+
+func (m Model) IsInputActive() bool {
+	return m.step != inputNone
+}
+
+func (m Model) Init() tea.Cmd {
+	return m.load(m.limit)
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case logLoadedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = styles.ErrorLineStyle.Render("Error: ") + styles.SubtitleStyle.Render(msg.err.Error())
+			return m, nil
+		}
+		m.status = ""
+		m.lines = msg.lines
+		m.limit = msg.limit
+		m.more = countCommits(msg.lines) >= msg.limit
+		if m.cursor >= len(m.lines) || m.lines[m.cursor].Commit == nil {
+			m.cursor = m.nextCommit(-1, 1)
+		}
+		return m, nil
+
+	case cherryPickDoneMsg:
+		if msg.stopped {
+			return m, tea.Batch(emitRefreshReflog, func() tea.Msg { return conflict.OpenMsg{} })
+		}
+		if msg.err != nil {
+			m.status = styles.ErrorLineStyle.Render("Cherry-pick failed: ") + styles.SubtitleStyle.Render(msg.err.Error())
+			return m, emitRefreshReflog
+		}
+		m.marked = make(map[string]bool)
+		m.status = styles.BadgeSuccess.Render(fmt.Sprintf("Cherry-picked %d commit(s) onto ", msg.count)) + styles.HighlightStyle.Render(msg.target)
+		return m, tea.Batch(emitRefreshReflog, m.load(m.limit))
+
+	case tea.KeyMsg:
+		if m.step != inputNone {
+			return m.handleInput(msg)
+		}
+		switch msg.String() {
+		case "esc":
+			if len(m.marked) > 0 {
+				m.marked = make(map[string]bool)
+				return m, nil
+			}
+			return m, func() tea.Msg { return CloseMsg{} }
+		case " ":
+			if c := m.selectedCommit(); c != nil {
+				if m.marked[c.Hash] {
+					delete(m.marked, c.Hash)
+				} else {
+					m.marked[c.Hash] = true
+				}
+				m.moveCursor(1)
+			}
+		case "p":
+			hashes := m.pickList()
+			if len(hashes) == 0 {
+				return m, nil
+			}
+			target := m.repo.CurrentBranch()
+			if target == "" {
+				target = "HEAD"
+			}
+			m.status = styles.BadgePending.Render(fmt.Sprintf("Cherry-picking %d commit(s) onto ", len(hashes))) + styles.HighlightStyle.Render(target)
+			return m, m.cherryPick(hashes, "", "")
+		case "P":
+			if len(m.pickList()) == 0 {
+				return m, nil
+			}
+			m.step = inputBranch
+			m.input.Prompt = "New branch: "
+			m.input.SetValue("")
+			m.input.Focus()
+			m.status = ""
+			return m, m.input.Cursor.BlinkCmd()
+		case "up", "k":
+			m.moveCursor(-1)
+		case "down", "j":
+			m.moveCursor(1)
+			return m, m.loadMoreIfNeeded()
+		case "pgup":
+			m.moveCursor(-m.visibleRows())
+		case "pgdown":
+			m.moveCursor(m.visibleRows())
+			return m, m.loadMoreIfNeeded()
+		case "home", "g":
+			m.cursor = m.nextCommit(-1, 1)
+		case "end", "G":
+			m.cursor = m.nextCommit(len(m.lines), -1)
+			return m, m.loadMoreIfNeeded()
+		case "c":
+			if m.base == "" {
+				m.status = styles.BadgeNeutral.Render("No default branch to compare with")
+				return m, nil
+			}
+			m.compare = !m.compare
+			m.cursor = 0
+			m.limit = pageSize
+			m.loading = true
+			return m, m.load(m.limit)
+		case "r":
+			m.loading = true
+			return m, m.load(m.limit)
+		}
+	}
+	return m, nil
+}
+
+func (m Model) handleInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.step = inputNone
+		return m, nil
+	case "enter":
+		value := strings.TrimSpace(m.input.Value())
+		if value == "" {
+			return m, nil
+		}
+		if m.step == inputBranch {
+			m.newName = value
+			m.step = inputBase
+			m.input.Prompt = "Base: "
+			m.input.SetValue(m.base)
+			m.input.CursorEnd()
+			return m, nil
+		}
+		m.step = inputNone
+		hashes := m.pickList()
+		m.status = styles.BadgePending.Render(fmt.Sprintf("Cherry-picking %d commit(s) onto new branch ", len(hashes))) + styles.HighlightStyle.Render(m.newName)
+		return m, m.cherryPick(hashes, m.newName, value)
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m Model) selectedCommit() *git.Commit {
+	if m.cursor < 0 || m.cursor >= len(m.lines) {
+		return nil
+	}
+	return m.lines[m.cursor].Commit
+}
+
+// pickList returns the marked commits, or the selected one when nothing is
+// marked, oldest first so they apply in their original order.
+func (m Model) pickList() []string {
+	var hashes []string
+	for i := len(m.lines) - 1; i >= 0; i-- {
+		if c := m.lines[i].Commit; c != nil && m.marked[c.Hash] {
+			hashes = append(hashes, c.Hash)
+		}
+	}
+	if len(hashes) == 0 {
+		if c := m.selectedCommit(); c != nil {
+			hashes = append(hashes, c.Hash)
+		}
+	}
+	return hashes
+}
+
+func (m *Model) moveCursor(delta int) {
+	step := 1
+	if delta < 0 {
+		step = -1
+	}
+	for n := 0; n != delta; n += step {
+		next := m.nextCommit(m.cursor, step)
+		if next < 0 || next >= len(m.lines) || m.lines[next].Commit == nil {
+			return
+		}
+		m.cursor = next
+	}
+}
+
+// nextCommit finds the next row from i in direction step that holds a commit.
+func (m Model) nextCommit(i, step int) int {
+	for j := i + step; j >= 0 && j < len(m.lines); j += step {
+		if m.lines[j].Commit != nil {
+			return j
+		}
+	}
+	if i < 0 || i >= len(m.lines) {
+		return 0
+	}
+	return i
+}
+
+func (m Model) loadMoreIfNeeded() tea.Cmd {
+	if !m.more || m.loading || m.nextCommit(m.cursor, 1) != m.cursor {
+		return nil
+	}
+	return m.load(m.limit + pageSize)
+}
+
+func (m Model) View() string {
+	title := styles.TitleStyle.Render("Log") + " " + styles.HighlightStyle.Render(m.branch)
+	if m.compare {
+		title += styles.SubtitleStyle.Render(fmt.Sprintf("  (%d commits not on %s)", countCommits(m.lines), m.base))
+	}
+	if len(m.marked) > 0 {
+		title += styles.BadgePending.Render(fmt.Sprintf("  %d marked", len(m.marked)))
+	}
+
+	var body string
+	if len(m.lines) == 0 && !m.loading {
+		body = styles.SubtitleStyle.Render("  No commits")
+	} else {
+		body = m.renderRows()
+	}
+
+	content := title + "\n\n" + body
+	if m.step != inputNone {
+		content += "\n" + lipgloss.NewStyle().MarginLeft(2).Render(m.input.View())
+	}
+	if m.status != "" {
+		content += "\n" + lipgloss.NewStyle().MarginLeft(2).Render(m.status)
+	}
+	return content
+}
+
+func (m Model) visibleRows() int {
+	return max(m.height-5, 1)
+}
+
+func (m Model) renderRows() string {
+	visible := m.visibleRows()
+	offset := 0
+	if m.cursor >= visible {
+		offset = m.cursor - visible + 1
+	}
+	end := min(offset+visible, len(m.lines))
+
+	rows := make([]string, 0, end-offset)
+	for i := offset; i < end; i++ {
+		rows = append(rows, m.renderLine(m.lines[i], i == m.cursor))
+	}
+	return strings.Join(rows, "\n")
+}
+
+func (m Model) renderLine(l git.LogLine, selected bool) string {
+	prefix := "  "
+	if selected {
+		prefix = styles.HighlightStyle.Render("> ")
+	}
+	if l.Commit != nil && m.marked[l.Commit.Hash] {
+		prefix = styles.BadgePending.Render("+ ")
+		if selected {
+			prefix = styles.BadgePending.Render("+>")
+		}
+	}
+	graph := renderGraph(l.Graph)
+	if l.Commit == nil {
+		return prefix + graph
+	}
+
+	c := l.Commit
+	hash := styles.SubtitleStyle.Render(c.ShortHash)
+	meta := styles.SubtitleStyle.Render(c.Author + ", " + c.TimeAgo)
+	refs := ""
+	if c.Refs != "" {
+		refs = styles.BadgePending.Render("("+c.Refs+")") + " "
+	}
+
+	used := lipgloss.Width(prefix) + lipgloss.Width(graph) + lipgloss.Width(hash) + lipgloss.Width(refs) + lipgloss.Width(meta) + 3
+	subject := truncate(c.Subject, m.width-used)
+	if selected {
+		subject = styles.HighlightStyle.Render(subject)
+	}
+	return prefix + graph + hash + " " + refs + subject + " " + meta
+}
+
+func renderGraph(graph string) string {
+	var b strings.Builder
+	for _, r := range graph {
+		switch r {
+		case '*':
+			b.WriteString(styles.HighlightStyle.Render("●"))
+		case ' ':
+			b.WriteRune(r)
+		default:
+			b.WriteString(styles.SubtitleStyle.Render(string(r)))
+		}
+	}
+	return b.String()
+}
+
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if width <= 3 {
+		return ""
+	}
+	if len(runes) <= width {
+		return s
+	}
+	return string(runes[:width-3]) + "..."
+}
+
+func countCommits(lines []git.LogLine) int {
+	n := 0
+	for _, l := range lines {
+		if l.Commit != nil {
+			n++
+		}
+	}
+	return n
+}
+
+func (m Model) load(limit int) tea.Cmd {
+	rev := m.branch
+	if m.compare {
+		rev = m.base + ".." + m.branch
+	}
+	return func() tea.Msg {
+		lines, err := m.repo.Log(rev, limit)
+		return logLoadedMsg{lines: lines, limit: limit, err: err}
+	}
+}
+
+func (m Model) cherryPick(hashes []string, newBranch, base string) tea.Cmd {
+	return func() tea.Msg {
+		target := m.repo.CurrentBranch()
+		if target == "" {
+			target = "HEAD"
+		}
+		if newBranch != "" {
+			if err := m.repo.CreateBranchFrom(newBranch, base); err != nil {
+				return cherryPickDoneMsg{err: err}
+			}
+			target = newBranch
+		}
+		err := m.repo.CherryPick(hashes...)
+		stopped := err != nil && m.repo.InProgress() == git.OpCherryPick
+		return cherryPickDoneMsg{count: len(hashes), target: target, stopped: stopped, err: err}
+	}
+}
+
+func emitRefreshReflog() tea.Msg {
+	return reflog.RefreshReflogMsg{}
+}
@@ -0,0 +1,279 @@
+package compare
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/elisa-content-delivery/hit/internal/git"
+	"github.com/elisa-content-delivery/hit/internal/styles"
+)
+
+// OpenMsg asks the app to compare Head against Base.
+type OpenMsg struct {
+	Base string
+	Head string
+}
+
+// CloseMsg returns to the view the comparison was opened from.
+type CloseMsg struct{}
+
+type comparedMsg struct {
+	cmp *git.Comparison
+	err error
+}
+
+type diffLoadedMsg struct {
+	base string
+	head string
+	diff string
+	err  error
+}
+
+type inputStep int
+
+const (
+	inputNone inputStep = iota
+	inputBase
+	inputHead
+)
+
+type Model struct {
+	repo     *git.Repo
+	base     string
+	head     string
+	cmp      *git.Comparison
+	diff     string
+	showDiff bool
+	loading  bool
+	view     viewport.Model
+	input    textinput.Model
+	step     inputStep
+	newBase  string
+	width    int
+	height   int
+	status   string
+}
+
+func New(repo *git.Repo, base, head string) Model {
+	vp := viewport.New(0, 0)
+	vp.HighPerformanceRendering = false
+
+	ti := textinput.New()
+	ti.CharLimit = 128
+
+	return Model{repo: repo, base: base, head: head, loading: true, view: vp, input: ti}
+}
+
+func (m Model) Init() tea.Cmd {
+	return m.load
+}
+
+func (m Model) IsInputActive() bool {
+	return m.step != inputNone
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.view.Width = msg.Width
+		m.view.Height = max(msg.Height-4, 1)
+		m.render()
+		return m, nil
+
+	case comparedMsg:
+		if msg.cmp != nil && (msg.cmp.Base != m.base || msg.cmp.Head != m.head) {
+			return m, nil
+		}
+		m.loading = false
+		if msg.err != nil {
+			m.cmp = nil
+			m.status = styles.ErrorLineStyle.Render("Compare failed: ") + styles.SubtitleStyle.Render(msg.err.Error())
+			m.render()
+			return m, nil
+		}
+		m.status = ""
+		m.cmp = msg.cmp
+		m.diff = ""
+		m.render()
+		m.view.GotoTop()
+		if m.showDiff {
+			return m, m.loadDiff
+		}
+		return m, nil
+
+	case diffLoadedMsg:
+		if msg.base != m.base || msg.head != m.head {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.status = styles.ErrorLineStyle.Render("Diff failed: ") + styles.SubtitleStyle.Render(msg.err.Error())
+			return m, nil
+		}
+		m.diff = msg.diff
+		m.render()
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.step != inputNone {
+			return m.handleInput(msg)
+		}
+		switch msg.String() {
+		case "esc":
+			return m, func() tea.Msg { return CloseMsg{} }
+		case "e":
+			m.step = inputBase
+			m.input.Prompt = "Base: "
+			m.input.SetValue(m.base)
+			m.input.CursorEnd()
+			m.input.Focus()
+			return m, m.input.Cursor.BlinkCmd()
+		case "s":
+			m.base, m.head = m.head, m.base
+			return m, m.reload()
+		case "d":
+			m.showDiff = !m.showDiff
+			m.render()
+			if m.showDiff && m.diff == "" && m.cmp != nil {
+				return m, m.loadDiff
+			}
+			return m, nil
+		case "r":
+			return m, m.reload()
+		}
+	}
+
+	var cmd tea.Cmd
+	m.view, cmd = m.view.Update(msg)
+	return m, cmd
+}
+
+func (m Model) handleInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.step = inputNone
+		return m, nil
+	case "enter":
+		value := strings.TrimSpace(m.input.Value())
+		if value == "" {
+			return m, nil
+		}
+		if m.step == inputBase {
+			m.newBase = value
+			m.step = inputHead
+			m.input.Prompt = "Compare: "
+			m.input.SetValue(m.head)
+			m.input.CursorEnd()
+			return m, nil
+		}
+		m.step = inputNone
+		m.base, m.head = m.newBase, value
+		return m, m.reload()
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) reload() tea.Cmd {
+	m.loading = true
+	m.status = styles.BadgePending.Render("Comparing...")
+	return m.load
+}
+
+// render lays out the summary, both commit lists and the changed files, or
+// the full patch in diff mode.
+func (m *Model) render() {
+	c := m.cmp
+	if c == nil {
+		m.view.SetContent("")
+		return
+	}
+
+	var b strings.Builder
+	if m.showDiff {
+		if m.diff == "" {
+			b.WriteString(styles.SubtitleStyle.Render("Loading diff..."))
+		} else {
+			b.WriteString(styles.Diff(m.diff))
+		}
+		m.view.SetContent(b.String())
+		return
+	}
+
+	section := func(title string, commits []git.Commit, count int) {
+		fmt.Fprintf(&b, "%s %s\n", styles.HighlightStyle.Render(title), styles.SubtitleStyle.Render(fmt.Sprintf("(%d)", count)))
+		if len(commits) == 0 {
+			b.WriteString(styles.SubtitleStyle.Render("  none") + "\n")
+		}
+		for _, commit := range commits {
+			fmt.Fprintf(&b, "  %s %s %s\n", styles.SubtitleStyle.Render(commit.ShortHash), commit.Subject,
+				styles.SubtitleStyle.Render("· "+commit.Author+" · "+commit.TimeAgo))
+		}
+		if count > len(commits) {
+			b.WriteString(styles.SubtitleStyle.Render(fmt.Sprintf("  … %d more", count-len(commits))) + "\n")
+		}
+		b.WriteString("\n")
+	}
+	section("On "+c.Head+", not on "+c.Base, c.HeadOnly, c.Ahead)
+	section("On "+c.Base+", not on "+c.Head, c.BaseOnly, c.Behind)
+
+	added, deleted := 0, 0
+	for _, f := range c.Files {
+		added += f.Added
+		deleted += f.Deleted
+	}
+	fmt.Fprintf(&b, "%s %s\n", styles.HighlightStyle.Render("Files changed on "+c.Head),
+		styles.SubtitleStyle.Render(fmt.Sprintf("(%d files, ", len(c.Files)))+
+			styles.DiffLine(fmt.Sprintf("+%d", added))+styles.SubtitleStyle.Render(" ")+
+			styles.DiffLine(fmt.Sprintf("-%d", deleted))+styles.SubtitleStyle.Render(")"))
+	for _, f := range c.Files {
+		stat := styles.SubtitleStyle.Render("binary")
+		if !f.Binary {
+			stat = styles.DiffLine(fmt.Sprintf("+%d", f.Added)) + " " + styles.DiffLine(fmt.Sprintf("-%d", f.Deleted))
+		}
+		fmt.Fprintf(&b, "  %s %s\n", f.Path, stat)
+	}
+	m.view.SetContent(b.String())
+}
+
+func (m Model) View() string {
+	title := styles.TitleStyle.Render("Compare") + " " + styles.HighlightStyle.Render(m.base) +
+		styles.SubtitleStyle.Render("...") + styles.HighlightStyle.Render(m.head)
+	if m.cmp != nil {
+		title += "  " + styles.BadgeSuccess.Render(fmt.Sprintf("%s%d", styles.IconArrowUp, m.cmp.Ahead)) +
+			" " + styles.BadgePending.Render(fmt.Sprintf("%s%d", styles.IconArrowDn, m.cmp.Behind))
+	}
+	if m.showDiff {
+		title += styles.SubtitleStyle.Render("  (diff)")
+	}
+
+	body := m.view.View()
+	if m.loading && m.cmp == nil {
+		body = styles.SubtitleStyle.Render("  Comparing...")
+	}
+
+	content := title + "\n\n" + body
+	if m.step != inputNone {
+		content += "\n" + lipgloss.NewStyle().MarginLeft(2).Render(m.input.View())
+	} else if m.status != "" {
+		content += "\n" + lipgloss.NewStyle().MarginLeft(2).Render(m.status)
+	}
+	return content
+}
+
+func (m Model) load() tea.Msg {
+	cmp, err := m.repo.Compare(m.base, m.head)
+	return comparedMsg{cmp: cmp, err: err}
+}
+
+func (m Model) loadDiff() tea.Msg {
+	diff, err := m.repo.CompareDiff(m.base, m.head)
+	return diffLoadedMsg{base: m.base, head: m.head, diff: diff, err: err}
+}
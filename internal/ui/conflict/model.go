@@ -0,0 +1,191 @@
+package conflict
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/elisa-content-delivery/hit/internal/git"
+	"github.com/elisa-content-delivery/hit/internal/styles"
+)
+
+// OpenMsg asks the app to show the conflict pane for the operation git
+// stopped in.
+type OpenMsg struct{}
+
+// ResolvedMsg is sent once the operation has been completed or aborted.
+type ResolvedMsg struct {
+	Op      git.Operation
+	Aborted bool
+}
+
+// CloseMsg is sent when the user leaves the pane with the operation still
+// in progress.
+type CloseMsg struct{}
+
+type stateLoadedMsg struct {
+	op    git.Operation
+	files []string
+	err   error
+}
+
+type actionDoneMsg struct {
+	action string
+	err    error
+}
+
+type editorClosedMsg struct {
+	err error
+}
+
+type fileItem struct{ path string }
+
+func (f fileItem) Title() string       { return styles.BadgeFailure.Render(styles.IconCross) + " " + f.path }
+func (f fileItem) Description() string { return "" }
+func (f fileItem) FilterValue() string { return f.path }
+
+type Model struct {
+	repo   *git.Repo
+	op     git.Operation
+	list   list.Model
+	width  int
+	height int
+	status string
+}
+
+func New(repo *git.Repo) Model {
+	d := list.NewDefaultDelegate()
+	d.ShowDescription = false
+	l := list.New(nil, d, 0, 0)
+	l.Title = "Conflicts"
+	l.SetShowHelp(false)
+	l.SetFilteringEnabled(false)
+	l.SetStatusBarItemName("file", "files")
+	l.Styles.Title = styles.TitleStyle
+	return Model{repo: repo, list: l}
+}
+
+func (m Model) Init() tea.Cmd {
+	return m.loadState
+}
+
+func (m Model) Operation() git.Operation {
+	return m.op
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.list.SetSize(msg.Width, msg.Height-4)
+		return m, nil
+
+	case stateLoadedMsg:
+		if msg.err != nil {
+			m.status = styles.ErrorLineStyle.Render("Error: ") + styles.SubtitleStyle.Render(msg.err.Error())
+			return m, nil
+		}
+		if msg.op == git.OpNone {
+			return m, func() tea.Msg { return ResolvedMsg{Op: m.op} }
+		}
+		m.op = msg.op
+		m.list.Title = fmt.Sprintf("Conflicts (%s in progress)", m.op)
+		items := make([]list.Item, len(msg.files))
+		for i, f := range msg.files {
+			items[i] = fileItem{path: f}
+		}
+		m.list.SetItems(items)
+		if len(items) == 0 && m.status == "" {
+			m.status = styles.BadgeSuccess.Render("No conflicts left") + styles.SubtitleStyle.Render(" · press c to continue")
+		}
+		return m, nil
+
+	case actionDoneMsg:
+		if msg.err != nil {
+			m.status = styles.ErrorLineStyle.Render(msg.action+" failed: ") + styles.SubtitleStyle.Render(msg.err.Error())
+			return m, m.loadState
+		}
+		if msg.action == "Abort" {
+			op := m.op
+			return m, func() tea.Msg { return ResolvedMsg{Op: op, Aborted: true} }
+		}
+		m.status = ""
+		return m, m.loadState
+
+	case editorClosedMsg:
+		if msg.err != nil {
+			m.status = styles.ErrorLineStyle.Render("Editor failed: ") + styles.SubtitleStyle.Render(msg.err.Error())
+		}
+		return m, m.loadState
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			return m, func() tea.Msg { return CloseMsg{} }
+
+		case "enter", "e":
+			selected, ok := m.list.SelectedItem().(fileItem)
+			if !ok {
+				return m, nil
+			}
+			return m, tea.ExecProcess(m.repo.EditorCmd(selected.path), func(err error) tea.Msg {
+				return editorClosedMsg{err: err}
+			})
+
+		case "m":
+			selected, ok := m.list.SelectedItem().(fileItem)
+			if !ok {
+				return m, nil
+			}
+			m.status = ""
+			return m, m.runAction("Mark resolved", func() error { return m.repo.MarkResolved(selected.path) })
+
+		case "c":
+			m.status = styles.BadgePending.Render("Continuing " + m.op.String() + "...")
+			return m, m.runAction("Continue", func() error { return m.repo.ContinueOperation(m.op) })
+
+		case "s":
+			if !m.op.CanSkip() {
+				return m, nil
+			}
+			m.status = styles.BadgePending.Render("Skipping commit...")
+			return m, m.runAction("Skip", func() error { return m.repo.SkipOperation(m.op) })
+
+		case "a":
+			m.status = styles.BadgePending.Render("Aborting " + m.op.String() + "...")
+			return m, m.runAction("Abort", func() error { return m.repo.AbortOperation(m.op) })
+
+		case "r":
+			return m, m.loadState
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m Model) View() string {
+	content := m.list.View()
+	if m.status != "" {
+		content += "\n" + lipgloss.NewStyle().MarginLeft(2).Render(m.status)
+	}
+	return content
+}
+
+func (m Model) loadState() tea.Msg {
+	op := m.repo.InProgress()
+	if op == git.OpNone {
+		return stateLoadedMsg{op: op}
+	}
+	files, err := m.repo.ConflictedFiles()
+	return stateLoadedMsg{op: op, files: files, err: err}
+}
+
+func (m Model) runAction(name string, fn func() error) tea.Cmd {
+	return func() tea.Msg {
+		return actionDoneMsg{action: name, err: fn()}
+	}
+}
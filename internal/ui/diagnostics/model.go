@@ -0,0 +1,173 @@
+package diagnostics
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	gh "github.com/elisa-content-delivery/hit/internal/github"
+	"github.com/elisa-content-delivery/hit/internal/styles"
+)
+
+// OpenMsg asks the app to show the token diagnostics.
+type OpenMsg struct{}
+
+// CloseMsg returns to the view the diagnostics were opened from.
+type CloseMsg struct{}
+
+// CheckedMsg carries the feature checks for a client. The app routes it to
+// the model whatever view is shown, so problems surface right after sign-in.
+type CheckedMsg struct {
+	client *gh.Client
+	checks []gh.FeatureCheck
+}
+
+type Model struct {
+	client   *gh.Client
+	info     gh.TokenInfo
+	checks   []gh.FeatureCheck
+	announce bool
+	spinner  spinner.Model
+	width    int
+	height   int
+}
+
+// New checks which features work with the token described by info.
+func New(client *gh.Client, info gh.TokenInfo) Model {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(styles.ColorSecondary)
+	return Model{client: client, info: info, spinner: s}
+}
+
+// NewStartup is New for a fresh sign-in: the diagnostics open on their own
+// when a feature will not work.
+func NewStartup(client *gh.Client, info gh.TokenInfo) Model {
+	m := New(client, info)
+	m.announce = true
+	return m
+}
+
+func (m Model) Init() tea.Cmd {
+	client, info := m.client, m.info
+	return tea.Batch(m.spinner.Tick, func() tea.Msg {
+		return CheckedMsg{client: client, checks: client.CheckFeatures(info)}
+	})
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case spinner.TickMsg:
+		if m.checks != nil {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case CheckedMsg:
+		if msg.client != m.client {
+			return m, nil
+		}
+		m.checks = msg.checks
+		if m.announce && m.missing() {
+			m.announce = false
+			return m, func() tea.Msg { return OpenMsg{} }
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "enter":
+			return m, func() tea.Msg { return CloseMsg{} }
+		}
+	}
+	return m, nil
+}
+
+// missing reports whether a feature is known not to work at all.
+func (m Model) missing() bool {
+	for _, c := range m.checks {
+		if c.Status == gh.CheckMissing {
+			return true
+		}
+	}
+	return false
+}
+
+func (m Model) View() string {
+	title := styles.TitleStyle.Render("Token Diagnostics")
+	if m.client == nil {
+		return title + "\n\n" + styles.SubtitleStyle.Render("  Sign in to GitHub to check the token")
+	}
+
+	var b strings.Builder
+	who := "an unverified token"
+	if m.info.Login != "" {
+		who = styles.HighlightStyle.Render(m.info.Login)
+	}
+	b.WriteString("Signed in to " + styles.HighlightStyle.Render(m.client.Host()) + " as " + who + "\n")
+	switch {
+	case m.info.Login == "":
+	case m.info.Scopes == nil:
+		b.WriteString(styles.SubtitleStyle.Render("Fine-grained or GitHub App token; permissions are probed") + "\n")
+	case len(m.info.Scopes) == 0:
+		b.WriteString(styles.SubtitleStyle.Render("Scopes: none") + "\n")
+	default:
+		b.WriteString(styles.SubtitleStyle.Render("Scopes: "+strings.Join(m.info.Scopes, ", ")) + "\n")
+	}
+	if !m.info.Expires.IsZero() {
+		b.WriteString(styles.SubtitleStyle.Render("Expires "+m.info.Expires.Format("2006-01-02")) + "\n")
+	}
+	b.WriteString("\n")
+
+	if m.checks == nil {
+		b.WriteString(m.spinner.View() + " Checking features...")
+	} else {
+		width := 0
+		for _, c := range m.checks {
+			width = max(width, len(c.Feature))
+		}
+		for _, c := range m.checks {
+			b.WriteString(fmt.Sprintf("%s  %-*s  %s\n", badge(c.Status), width, c.Feature, detail(c)))
+		}
+		if m.missing() {
+			b.WriteString("\n" + styles.SubtitleStyle.Render("Grant the missing scopes to the token, or press A to switch to another one."))
+		}
+	}
+
+	body := lipgloss.NewStyle().MarginLeft(2).Render(b.String())
+	return lipgloss.JoinVertical(lipgloss.Left, title, "", body)
+}
+
+func badge(status gh.CheckStatus) string {
+	switch status {
+	case gh.CheckOK:
+		return styles.BadgeSuccess.Render(styles.IconCheck)
+	case gh.CheckLimited:
+		return styles.BadgePending.Render("~")
+	case gh.CheckMissing:
+		return styles.BadgeFailure.Render(styles.IconCross)
+	}
+	return styles.BadgeNeutral.Render("?")
+}
+
+func detail(c gh.FeatureCheck) string {
+	var parts []string
+	if c.Status == gh.CheckOK {
+		parts = append(parts, "works")
+	} else if c.Need != "" {
+		parts = append(parts, "needs "+styles.HighlightStyle.Render(c.Need))
+	}
+	if c.Note != "" {
+		parts = append(parts, styles.SubtitleStyle.Render(c.Note))
+	}
+	return strings.Join(parts, styles.SubtitleStyle.Render(" · "))
+}
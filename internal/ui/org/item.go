@@ -7,6 +7,47 @@ import (
 	"github.com/elisa-content-delivery/hit/internal/styles"
 )
 
+// source is a list of repositories that is not an organization's.
+type source int
+
+const (
+	sourceOwn source = iota
+	sourceCollaborator
+	sourceStarred
+)
+
+type sourceItem struct{ source source }
+
+func (s sourceItem) name() string {
+	switch s.source {
+	case sourceCollaborator:
+		return "Collaborating on"
+	case sourceStarred:
+		return "Starred"
+	}
+	return "Your repositories"
+}
+
+func (s sourceItem) Title() string {
+	switch s.source {
+	case sourceCollaborator:
+		return styles.IconRepo + " " + s.name()
+	case sourceStarred:
+		return styles.IconStar + " " + s.name()
+	}
+	return styles.IconUser + " " + s.name()
+}
+func (s sourceItem) Description() string {
+	switch s.source {
+	case sourceCollaborator:
+		return "Repositories of others you can push to"
+	case sourceStarred:
+		return "Repositories you starred"
+	}
+	return "Repositories you own"
+}
+func (s sourceItem) FilterValue() string { return s.name() }
+
 type orgItem struct{ org gh.Org }
 
 func (o orgItem) Title() string {
@@ -15,10 +56,18 @@ func (o orgItem) Title() string {
 func (o orgItem) Description() string { return o.org.Description }
 func (o orgItem) FilterValue() string { return o.org.Login }
 
-type repoItem struct{ repo gh.OrgRepo }
+// repoItem shows the owner too when full is set, for lists that span
+// organizations.
+type repoItem struct {
+	repo gh.OrgRepo
+	full bool
+}
 
 func (r repoItem) Title() string {
 	name := r.repo.Name
+	if r.full {
+		name = r.repo.FullName
+	}
 	var badges string
 	if r.repo.Archived {
 		badges += " " + styles.BadgeNeutral.Render("[archived]")
@@ -28,5 +77,17 @@ func (r repoItem) Title() string {
 	}
 	return fmt.Sprintf("%s%s", name, badges)
 }
-func (r repoItem) Description() string { return r.repo.Description }
-func (r repoItem) FilterValue() string { return r.repo.Name }
+func (r repoItem) Description() string {
+	if r.repo.DefaultBranch == "" {
+		return r.repo.Description
+	}
+	branch := styles.IconBranch + " " + r.repo.DefaultBranch
+	if badge := styles.CheckBadge(r.repo.CIStatus); badge != "" {
+		branch += " " + badge
+	}
+	if r.repo.Description == "" {
+		return branch
+	}
+	return branch + "  " + r.repo.Description
+}
+func (r repoItem) FilterValue() string { return r.repo.FullName }
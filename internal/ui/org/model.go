@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
@@ -13,6 +14,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	gh "github.com/elisa-content-delivery/hit/internal/github"
 	"github.com/elisa-content-delivery/hit/internal/styles"
+	"github.com/elisa-content-delivery/hit/internal/ui/paging"
 )
 
 type pane int
@@ -22,21 +24,18 @@ const (
 	paneRepos
 )
 
-type orgsLoadedMsg struct {
-	orgs []gh.Org
-	err  error
-}
-
-type reposLoadedMsg struct {
-	repos []gh.OrgRepo
-	err   error
-}
-
 type cloneDoneMsg struct {
 	repoName string
+	path     string
 	err      error
 }
 
+// ClonedMsg reports a finished clone so the app can open it when hit was
+// started outside a repository.
+type ClonedMsg struct {
+	Path string
+}
+
 type Model struct {
 	client           *gh.Client
 	currentPane      pane
@@ -44,7 +43,15 @@ type Model struct {
 	reposList        list.Model
 	spinner          spinner.Model
 	loading          bool
-	selectedOrg      *gh.Org
+	orgsPager        *gh.Pager[gh.Org]
+	reposPager       *gh.Pager[gh.OrgRepo]
+	loadingMore      bool
+	refreshing       bool
+	scope            string
+	newReposPager    func() *gh.Pager[gh.OrgRepo]
+	fullNames        bool
+	searching        bool
+	searchInput      textinput.Model
 	showCloneOverlay bool
 	cloneInput       textinput.Model
 	cloneSSHURL      string
@@ -55,6 +62,8 @@ type Model struct {
 	status           string
 }
 
+// New browses the user's organizations and repositories. client may be nil
+// before authentication.
 func New(client *gh.Client) Model {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
@@ -64,7 +73,7 @@ func New(client *gh.Client) Model {
 		l := list.New(nil, list.NewDefaultDelegate(), 0, 0)
 		l.Title = title
 		l.SetShowHelp(false)
-		l.SetFilteringEnabled(false)
+		l.SetFilteringEnabled(true)
 		l.Styles.Title = styles.TitleStyle
 		return l
 	}
@@ -73,24 +82,48 @@ func New(client *gh.Client) Model {
 	ti.Prompt = "Path: "
 	ti.CharLimit = 256
 
+	si := textinput.New()
+	si.Prompt = "Search: "
+	si.Placeholder = "name, topic:cli, language:go, org:acme"
+	si.CharLimit = 256
+
 	return Model{
 		client:      client,
 		currentPane: paneOrgs,
-		orgsList:    makeList("Organizations"),
+		orgsList:    makeList("Browse"),
+		loading:     client != nil,
 		reposList:   makeList("Repos"),
 		spinner:     s,
 		cloneInput:  ti,
+		searchInput: si,
 	}
 }
 
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(m.spinner.Tick, m.loadOrgs)
+	if m.client == nil {
+		return nil
+	}
+	pager := m.client.UserOrgs()
+	return tea.Batch(m.spinner.Tick, paging.LoadCached(pager), paging.Load(pager, false))
 }
 
 func (m Model) IsOverlayActive() bool {
 	return m.showCloneOverlay
 }
 
+// IsInputActive reports whether keys go to the clone path, the search query
+// or the list filter.
+func (m Model) IsInputActive() bool {
+	return m.showCloneOverlay || m.searching || m.activeList().FilterState() == list.Filtering
+}
+
+func (m Model) activeList() list.Model {
+	if m.currentPane == paneRepos {
+		return m.reposList
+	}
+	return m.orgsList
+}
+
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
@@ -106,33 +139,60 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		m.spinner, cmd = m.spinner.Update(msg)
 		return m, cmd
 
-	case orgsLoadedMsg:
+	case paging.Msg[gh.Org]:
+		if msg.More && msg.Pager != m.orgsPager || msg.Cached && !m.loading {
+			return m, nil
+		}
+		if !msg.More {
+			m.orgsPager = msg.Pager
+		}
 		m.loading = false
-		if msg.err != nil {
-			m.status = fmt.Sprintf("Error: %s", msg.err)
+		m.refreshing = msg.Cached
+		m.loadingMore = false
+		if msg.Err != nil {
+			m.status = fmt.Sprintf("Error: %s", msg.Err)
+			// the user's own lists work without organizations
+			if len(m.orgsList.Items()) == 0 {
+				return m, m.orgsList.SetItems(sourceItems())
+			}
 			return m, nil
 		}
-		items := make([]list.Item, len(msg.orgs))
-		for i, o := range msg.orgs {
-			items[i] = orgItem{org: o}
+		m.status = ""
+		if msg.Cached {
+			m.status = "Refreshing..."
 		}
-		m.orgsList.SetItems(items)
-		return m, nil
+		items := msg.ListItems(m.orgsList.Items(), func(o gh.Org) list.Item { return orgItem{org: o} })
+		if !msg.More {
+			items = append(sourceItems(), items...)
+		}
+		cmd := m.orgsList.SetItems(items)
+		return m, tea.Batch(cmd, m.loadMore())
 
-	case reposLoadedMsg:
+	case paging.Msg[gh.OrgRepo]:
+		if msg.Pager != m.reposPager || msg.Cached && !m.loading {
+			return m, nil
+		}
 		m.loading = false
-		if msg.err != nil {
-			m.status = fmt.Sprintf("Error: %s", msg.err)
-			m.currentPane = paneOrgs
+		m.refreshing = msg.Cached
+		m.loadingMore = false
+		if msg.Err != nil {
+			m.status = fmt.Sprintf("Error: %s", msg.Err)
+			if !msg.More {
+				m.currentPane = paneOrgs
+			}
 			return m, nil
 		}
-		items := make([]list.Item, len(msg.repos))
-		for i, r := range msg.repos {
-			items[i] = repoItem{repo: r}
+		m.status = ""
+		if msg.Cached {
+			m.status = "Refreshing..."
 		}
-		m.reposList.SetItems(items)
+		items := msg.ListItems(m.reposList.Items(), func(r gh.OrgRepo) list.Item { return repoItem{repo: r, full: m.fullNames} })
+		if len(items) == 0 {
+			m.status = "No repositories found"
+		}
+		cmd := m.reposList.SetItems(items)
 		m.currentPane = paneRepos
-		return m, nil
+		return m, tea.Batch(cmd, m.loadMore())
 
 	case cloneDoneMsg:
 		m.cloning = false
@@ -141,25 +201,50 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			m.status = fmt.Sprintf("Clone failed: %s", msg.err)
 		} else {
 			m.status = fmt.Sprintf("Cloned %s successfully", msg.repoName)
+			path := msg.path
+			return m, func() tea.Msg { return ClonedMsg{Path: path} }
 		}
 		return m, nil
 
 	case tea.KeyMsg:
+		if m.client == nil {
+			return m, nil
+		}
 		if m.showCloneOverlay {
 			return m.handleOverlayKey(msg)
 		}
+		if m.searching {
+			return m.handleSearchKey(msg)
+		}
+		if m.activeList().FilterState() == list.Filtering {
+			break
+		}
 
 		switch msg.String() {
 		case "esc":
+			if m.activeList().FilterState() == list.FilterApplied {
+				// the list clears the filter first
+				break
+			}
 			return m.goBack()
 		case "enter":
 			return m.drillDown()
+		case "s":
+			m.searching = true
+			m.searchInput.SetValue("")
+			m.searchInput.Focus()
+			return m, textinput.Blink
 		case "r":
-			if m.currentPane == paneOrgs {
-				m.loading = true
-				m.status = ""
-				return m, tea.Batch(m.spinner.Tick, m.loadOrgs)
+			m.loading = true
+			m.loadingMore = false
+			m.refreshing = false
+			m.status = ""
+			if m.currentPane == paneRepos && m.newReposPager != nil {
+				m.reposPager = m.newReposPager()
+				return m, tea.Batch(m.spinner.Tick, paging.Load(m.reposPager, false))
 			}
+			m.orgsPager = m.client.UserOrgs()
+			return m, tea.Batch(m.spinner.Tick, paging.Load(m.orgsPager, false))
 		}
 	}
 
@@ -170,10 +255,48 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	case paneRepos:
 		m.reposList, cmd = m.reposList.Update(msg)
 	}
-	return m, cmd
+	return m, tea.Batch(cmd, m.loadMore())
+}
+
+// loadMore fetches the next page of the current list once the selection
+// reaches its last item. A filter only matches what is loaded, so while one
+// is set the remaining pages are fetched one after another.
+func (m *Model) loadMore() tea.Cmd {
+	if m.loading || m.loadingMore || m.refreshing {
+		return nil
+	}
+	switch m.currentPane {
+	case paneOrgs:
+		if m.orgsPager == nil || !m.orgsPager.HasNext() || !atEnd(m.orgsList) {
+			return nil
+		}
+		m.loadingMore = true
+		m.status = "Loading more organizations..."
+		return paging.Load(m.orgsPager, true)
+	case paneRepos:
+		if m.reposPager == nil || !m.reposPager.HasNext() {
+			return nil
+		}
+		if !atEnd(m.reposList) && m.reposList.FilterState() == list.Unfiltered {
+			return nil
+		}
+		m.loadingMore = true
+		m.status = "Loading more repos..."
+		return paging.Load(m.reposPager, true)
+	}
+	return nil
+}
+
+func atEnd(l list.Model) bool {
+	n := len(l.Items())
+	return n > 0 && l.Index() == n-1
 }
 
 func (m Model) View() string {
+	if m.client == nil {
+		return styles.TitleStyle.Render("Browse") + "\n\n" +
+			styles.SubtitleStyle.Render("  Sign in to GitHub to browse organizations and repositories")
+	}
 	if m.loading && !m.cloning {
 		return m.spinner.View() + " Loading..."
 	}
@@ -186,7 +309,9 @@ func (m Model) View() string {
 		content = m.reposList.View()
 	}
 
-	if m.status != "" {
+	if m.searching {
+		content += "\n" + lipgloss.NewStyle().MarginLeft(2).Render(m.searchInput.View())
+	} else if m.status != "" {
 		content += "\n" + lipgloss.NewStyle().MarginLeft(2).Render(m.status)
 	}
 
@@ -202,8 +327,8 @@ func (m Model) View() string {
 
 func (m Model) breadcrumb() string {
 	parts := []string{styles.SubtitleStyle.Render("Org")}
-	if m.selectedOrg != nil {
-		parts = append(parts, styles.HighlightStyle.Render(m.selectedOrg.Login))
+	if m.currentPane == paneRepos && m.scope != "" {
+		parts = append(parts, styles.HighlightStyle.Render(m.scope))
 	}
 	result := parts[0]
 	for _, p := range parts[1:] {
@@ -215,7 +340,7 @@ func (m Model) breadcrumb() string {
 func (m Model) goBack() (Model, tea.Cmd) {
 	if m.currentPane == paneRepos {
 		m.currentPane = paneOrgs
-		m.selectedOrg = nil
+		m.scope = ""
 		m.status = ""
 	}
 	return m, nil
@@ -224,13 +349,21 @@ func (m Model) goBack() (Model, tea.Cmd) {
 func (m Model) drillDown() (Model, tea.Cmd) {
 	switch m.currentPane {
 	case paneOrgs:
-		selected, ok := m.orgsList.SelectedItem().(orgItem)
-		if !ok {
-			return m, nil
+		switch selected := m.orgsList.SelectedItem().(type) {
+		case sourceItem:
+			newPager := m.client.UserRepos
+			switch selected.source {
+			case sourceCollaborator:
+				newPager = m.client.CollaboratorRepos
+			case sourceStarred:
+				newPager = m.client.StarredRepos
+			}
+			return m.openRepos(selected.name(), true, newPager)
+		case orgItem:
+			client, login := m.client, selected.org.Login
+			return m.openRepos(login, false, func() *gh.Pager[gh.OrgRepo] { return client.OrgRepos(login) })
 		}
-		m.selectedOrg = &selected.org
-		m.loading = true
-		return m, tea.Batch(m.spinner.Tick, m.loadRepos(selected.org.Login))
+		return m, nil
 
 	case paneRepos:
 		selected, ok := m.reposList.SelectedItem().(repoItem)
@@ -249,6 +382,45 @@ func (m Model) drillDown() (Model, tea.Cmd) {
 	return m, nil
 }
 
+// openRepos shows the repositories newPager pages through. scope names the
+// list in the breadcrumb, full shows owners for lists that span
+// organizations, and newPager starts the list over on refresh.
+func (m Model) openRepos(scope string, full bool, newPager func() *gh.Pager[gh.OrgRepo]) (Model, tea.Cmd) {
+	m.scope = scope
+	m.fullNames = full
+	m.newReposPager = newPager
+	m.loading = true
+	m.loadingMore = false
+	m.refreshing = false
+	m.status = ""
+	m.reposPager = newPager()
+	m.reposList.ResetFilter()
+	m.reposList.ResetSelected()
+	return m, tea.Batch(m.spinner.Tick, paging.LoadCached(m.reposPager), paging.Load(m.reposPager, false))
+}
+
+func (m Model) handleSearchKey(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.searching = false
+		m.searchInput.Blur()
+		return m, nil
+	case "enter":
+		query := strings.TrimSpace(m.searchInput.Value())
+		if query == "" {
+			return m, nil
+		}
+		m.searching = false
+		m.searchInput.Blur()
+		client := m.client
+		return m.openRepos("Search: "+query, true, func() *gh.Pager[gh.OrgRepo] { return client.SearchRepos(query) })
+	}
+
+	var cmd tea.Cmd
+	m.searchInput, cmd = m.searchInput.Update(msg)
+	return m, cmd
+}
+
 func (m Model) handleOverlayKey(msg tea.KeyMsg) (Model, tea.Cmd) {
 	switch msg.String() {
 	case "esc":
@@ -292,22 +464,15 @@ func (m Model) renderOverlay(bg string) string {
 	)
 }
 
-func (m Model) loadOrgs() tea.Msg {
-	orgs, err := m.client.GetUserOrgs()
-	return orgsLoadedMsg{orgs: orgs, err: err}
-}
-
-func (m Model) loadRepos(orgLogin string) tea.Cmd {
-	return func() tea.Msg {
-		repos, err := m.client.GetOrgRepos(orgLogin)
-		return reposLoadedMsg{repos: repos, err: err}
-	}
+// sourceItems head the list, above the organizations.
+func sourceItems() []list.Item {
+	return []list.Item{sourceItem{sourceOwn}, sourceItem{sourceCollaborator}, sourceItem{sourceStarred}}
 }
 
 func (m Model) cloneRepo(sshURL, targetPath, repoName string) tea.Cmd {
 	return func() tea.Msg {
 		cmd := exec.Command("git", "clone", sshURL, targetPath)
 		err := cmd.Run()
-		return cloneDoneMsg{repoName: repoName, err: err}
+		return cloneDoneMsg{repoName: repoName, path: targetPath, err: err}
 	}
 }
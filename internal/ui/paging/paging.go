@@ -0,0 +1,55 @@
+// Package paging feeds the pages of a gh.Pager to the list views: the first
+// page from the disk cache while the network request is in flight, then
+// further pages as the selection reaches the end of the list.
+package paging
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	gh "github.com/elisa-content-delivery/hit/internal/github"
+)
+
+// Msg carries one page of a list. More is set when the page extends the
+// list rather than replacing it, Cached when it was read from disk ahead of
+// the network response.
+type Msg[T any] struct {
+	Pager  *gh.Pager[T]
+	Items  []T
+	More   bool
+	Cached bool
+	Err    error
+}
+
+// Load fetches the next page of pager; more tells whether it extends the
+// list.
+func Load[T any](pager *gh.Pager[T], more bool) tea.Cmd {
+	return func() tea.Msg {
+		items, err := pager.Next()
+		return Msg[T]{Pager: pager, Items: items, More: more, Err: err}
+	}
+}
+
+// LoadCached delivers the first page of pager as stored by the last session,
+// or nothing when it was never fetched.
+func LoadCached[T any](pager *gh.Pager[T]) tea.Cmd {
+	return func() tea.Msg {
+		items, ok := pager.Cached()
+		if !ok {
+			return nil
+		}
+		return Msg[T]{Pager: pager, Items: items, Cached: true}
+	}
+}
+
+// ListItems is the list once msg is applied: the shown items followed by the
+// page when it extends the list, the page alone otherwise.
+func (msg Msg[T]) ListItems(shown []list.Item, item func(T) list.Item) []list.Item {
+	var items []list.Item
+	if msg.More {
+		items = shown
+	}
+	for _, v := range msg.Items {
+		items = append(items, item(v))
+	}
+	return items
+}
@@ -0,0 +1,67 @@
+package pr
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	gh "github.com/elisa-content-delivery/hit/internal/github"
+	"github.com/elisa-content-delivery/hit/internal/styles"
+)
+
+type prItem struct{ pr gh.PullRequest }
+
+func (p prItem) Title() string {
+	title := fmt.Sprintf("#%d %s", p.pr.Number, p.pr.Title)
+	if p.pr.Draft {
+		title += " " + styles.BadgeNeutral.Render("[draft]")
+	}
+	if badge := styles.CheckBadge(p.pr.Checks); badge != "" {
+		title = badge + " " + title
+	}
+	return title
+}
+
+func (p prItem) Description() string {
+	parts := []string{
+		p.pr.Author,
+		styles.HighlightStyle.Render(styles.IconBranch+" "+p.pr.HeadRef) + " → " + p.pr.BaseRef,
+		timeAgo(p.pr.UpdatedAt),
+	}
+	switch p.pr.ReviewDecision {
+	case "APPROVED":
+		parts = append(parts, styles.BadgeSuccess.Render("approved"))
+	case "CHANGES_REQUESTED":
+		parts = append(parts, styles.BadgeFailure.Render("changes requested"))
+	case "REVIEW_REQUIRED":
+		parts = append(parts, styles.BadgePending.Render("review required"))
+	}
+	if p.pr.ReviewsRequested > 0 {
+		parts = append(parts, plural(p.pr.ReviewsRequested, "review request"))
+	}
+	return strings.Join(parts, " · ")
+}
+
+func (p prItem) FilterValue() string { return p.pr.Title }
+
+// timeAgo matches git's relative dates used elsewhere, e.g. "2 hours ago".
+func timeAgo(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return plural(int(d.Minutes()), "minute") + " ago"
+	case d < 24*time.Hour:
+		return plural(int(d.Hours()), "hour") + " ago"
+	default:
+		return plural(int(d.Hours()/24), "day") + " ago"
+	}
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", word)
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
@@ -1,21 +1,53 @@
 package pr
 
 import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	gh "github.com/elisa-content-delivery/hit/internal/github"
 	"github.com/elisa-content-delivery/hit/internal/styles"
+	"github.com/elisa-content-delivery/hit/internal/ui/paging"
 )
 
 type Model struct {
-	width  int
-	height int
+	client      *gh.Client
+	list        list.Model
+	pager       *gh.Pager[gh.PullRequest]
+	spinner     spinner.Model
+	loading     bool
+	loadingMore bool
+	refreshing  bool
+	width       int
+	height      int
+	status      string
 }
 
-func New() Model {
-	return Model{}
+// New lists the repository's open pull requests. client may be nil before
+// authentication.
+func New(client *gh.Client) Model {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(styles.ColorSecondary)
+
+	l := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	l.Title = "Pull Requests"
+	l.SetShowHelp(false)
+	l.SetFilteringEnabled(false)
+	l.Styles.Title = styles.TitleStyle
+
+	return Model{client: client, list: l, spinner: s, loading: client != nil}
 }
 
+// Init loads the first page afresh; the list is replaced once it arrives.
 func (m Model) Init() tea.Cmd {
-	return nil
+	if m.client == nil {
+		return nil
+	}
+	pager := m.client.PullRequests()
+	return tea.Batch(m.spinner.Tick, paging.LoadCached(pager), paging.Load(pager, false))
 }
 
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
@@ -23,11 +55,81 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		m.list.SetSize(msg.Width, msg.Height-2)
+		return m, nil
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case paging.Msg[gh.PullRequest]:
+		if msg.More && msg.Pager != m.pager || msg.Cached && !m.loading {
+			return m, nil
+		}
+		if !msg.More {
+			m.pager = msg.Pager
+		}
+		m.loading = false
+		m.loadingMore = false
+		m.refreshing = msg.Cached
+		if msg.Err != nil {
+			m.status = fmt.Sprintf("Error: %s", msg.Err)
+			return m, nil
+		}
+		m.status = ""
+		if msg.Cached {
+			m.status = "Refreshing..."
+		}
+		items := msg.ListItems(m.list.Items(), func(pr gh.PullRequest) list.Item { return prItem{pr: pr} })
+		m.list.SetItems(items)
+		if len(items) == 0 {
+			m.status = "No open pull requests"
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "r" && m.client != nil {
+			m.loading = true
+			m.loadingMore = false
+			m.refreshing = false
+			m.status = ""
+			m.pager = m.client.PullRequests()
+			return m, tea.Batch(m.spinner.Tick, paging.Load(m.pager, false))
+		}
 	}
-	return m, nil
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, tea.Batch(cmd, m.loadMore())
+}
+
+// loadMore fetches the next page once the selection reaches the last loaded
+// pull request.
+func (m *Model) loadMore() tea.Cmd {
+	if m.pager == nil || m.loading || m.loadingMore || m.refreshing || !m.pager.HasNext() {
+		return nil
+	}
+	n := len(m.list.Items())
+	if n == 0 || m.list.Index() != n-1 {
+		return nil
+	}
+	m.loadingMore = true
+	m.status = "Loading more pull requests..."
+	return paging.Load(m.pager, true)
 }
 
 func (m Model) View() string {
-	return styles.TitleStyle.Render("Pull Requests") + "\n\n" +
-		styles.SubtitleStyle.Render("  Coming soon")
+	if m.client == nil {
+		return styles.TitleStyle.Render("Pull Requests") + "\n\n" +
+			styles.SubtitleStyle.Render("  Sign in to GitHub to list pull requests")
+	}
+	if m.loading {
+		return m.spinner.View() + " Loading..."
+	}
+	content := m.list.View()
+	if m.status != "" {
+		content += "\n" + lipgloss.NewStyle().MarginLeft(2).Render(m.status)
+	}
+	return content
 }
@@ -0,0 +1,285 @@
+package rebase
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/elisa-content-delivery/hit/internal/git"
+	"github.com/elisa-content-delivery/hit/internal/styles"
+	"github.com/elisa-content-delivery/hit/internal/ui/conflict"
+	"github.com/elisa-content-delivery/hit/internal/ui/reflog"
+)
+
+// OpenMsg asks the app to show the interactive rebase editor for the
+// current branch.
+type OpenMsg struct{}
+
+// DoneMsg is sent when the editor closes, after a rebase or on cancel.
+type DoneMsg struct{}
+
+type commitsLoadedMsg struct {
+	base    string
+	commits []git.Commit
+	err     error
+}
+
+type rebaseDoneMsg struct {
+	err error
+}
+
+var actionKeys = map[string]git.RebaseAction{
+	"p": git.RebasePick,
+	"r": git.RebaseReword,
+	"e": git.RebaseEdit,
+	"s": git.RebaseSquash,
+	"f": git.RebaseFixup,
+	"d": git.RebaseDrop,
+}
+
+var actionCycle = []git.RebaseAction{
+	git.RebasePick, git.RebaseReword, git.RebaseEdit, git.RebaseSquash, git.RebaseFixup, git.RebaseDrop,
+}
+
+type Model struct {
+	repo    *git.Repo
+	branch  string
+	onto    string
+	base    string
+	steps   []git.RebaseStep
+	cursor  int
+	loading bool
+	running bool
+	width   int
+	height  int
+	status  string
+}
+
+func New(repo *git.Repo) Model {
+	branch := repo.CurrentBranch()
+	if branch == "" {
+		branch = "HEAD"
+	}
+	return Model{
+		repo:    repo,
+		branch:  branch,
+		onto:    repo.DefaultBranchRef(),
+		loading: true,
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	return m.loadCommits
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case commitsLoadedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = styles.ErrorLineStyle.Render("Error: ") + styles.SubtitleStyle.Render(msg.err.Error())
+			return m, nil
+		}
+		m.base = msg.base
+		m.steps = make([]git.RebaseStep, len(msg.commits))
+		for i, c := range msg.commits {
+			m.steps[i] = git.RebaseStep{Action: git.RebasePick, Commit: c}
+		}
+		return m, nil
+
+	case rebaseDoneMsg:
+		m.running = false
+		if m.repo.InProgress() == git.OpRebase {
+			return m, tea.Batch(emitRefreshReflog, func() tea.Msg { return conflict.OpenMsg{} })
+		}
+		if msg.err != nil {
+			m.status = styles.ErrorLineStyle.Render("Rebase failed: ") + styles.SubtitleStyle.Render(msg.err.Error())
+			return m, nil
+		}
+		return m, tea.Batch(emitRefreshReflog, func() tea.Msg { return DoneMsg{} })
+
+	case tea.KeyMsg:
+		if m.running {
+			return m, nil
+		}
+		key := msg.String()
+		if action, ok := actionKeys[key]; ok && len(m.steps) > 0 {
+			m.steps[m.cursor].Action = action
+			m.status = ""
+			return m, nil
+		}
+		switch key {
+		case "esc":
+			return m, func() tea.Msg { return DoneMsg{} }
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.steps)-1 {
+				m.cursor++
+			}
+		case "K", "shift+up":
+			if m.cursor > 0 {
+				m.steps[m.cursor], m.steps[m.cursor-1] = m.steps[m.cursor-1], m.steps[m.cursor]
+				m.cursor--
+			}
+		case "J", "shift+down":
+			if m.cursor < len(m.steps)-1 {
+				m.steps[m.cursor], m.steps[m.cursor+1] = m.steps[m.cursor+1], m.steps[m.cursor]
+				m.cursor++
+			}
+		case " ":
+			if len(m.steps) > 0 {
+				m.steps[m.cursor].Action = nextAction(m.steps[m.cursor].Action)
+			}
+		case "enter":
+			return m.execute()
+		}
+	}
+	return m, nil
+}
+
+func nextAction(a git.RebaseAction) git.RebaseAction {
+	for i, c := range actionCycle {
+		if c == a {
+			return actionCycle[(i+1)%len(actionCycle)]
+		}
+	}
+	return git.RebasePick
+}
+
+func (m Model) execute() (Model, tea.Cmd) {
+	if len(m.steps) == 0 {
+		return m, nil
+	}
+	if err := validate(m.steps); err != nil {
+		m.status = styles.ErrorLineStyle.Render(err.Error())
+		return m, nil
+	}
+	cmd, err := m.repo.RebaseInteractiveCmd(m.base, m.steps)
+	if err != nil {
+		m.status = styles.ErrorLineStyle.Render("Rebase failed: ") + styles.SubtitleStyle.Render(err.Error())
+		return m, nil
+	}
+	m.running = true
+	m.status = styles.BadgePending.Render("Rebasing...")
+
+	for _, s := range m.steps {
+		if s.Action.NeedsEditor() {
+			return m, tea.ExecProcess(cmd, func(err error) tea.Msg {
+				return rebaseDoneMsg{err: err}
+			})
+		}
+	}
+	return m, func() tea.Msg {
+		return rebaseDoneMsg{err: runCombined(cmd)}
+	}
+}
+
+// validate rejects todo lists git would refuse: squash and fixup need an
+// earlier commit to fold into.
+func validate(steps []git.RebaseStep) error {
+	for _, s := range steps {
+		switch s.Action {
+		case git.RebaseDrop:
+			continue
+		case git.RebaseSquash, git.RebaseFixup:
+			return fmt.Errorf("cannot %s %s: no earlier commit to fold into", s.Action, s.Commit.ShortHash)
+		}
+		return nil
+	}
+	return nil
+}
+
+func (m Model) View() string {
+	title := styles.TitleStyle.Render("Interactive rebase") + " " + styles.HighlightStyle.Render(m.branch)
+	if m.onto != "" {
+		title += styles.SubtitleStyle.Render(" · commits ahead of " + m.onto + ", oldest first")
+	}
+
+	var body string
+	switch {
+	case m.loading:
+		body = styles.SubtitleStyle.Render("  Loading...")
+	case len(m.steps) == 0 && m.status == "":
+		body = styles.SubtitleStyle.Render("  No commits ahead of " + m.onto)
+	default:
+		body = m.renderSteps()
+	}
+
+	content := title + "\n\n" + body
+	if m.status != "" {
+		content += "\n\n" + lipgloss.NewStyle().MarginLeft(2).Render(m.status)
+	}
+	return content
+}
+
+func (m Model) renderSteps() string {
+	visible := max(m.height-5, 1)
+	offset := 0
+	if m.cursor >= visible {
+		offset = m.cursor - visible + 1
+	}
+	end := min(offset+visible, len(m.steps))
+
+	rows := make([]string, 0, end-offset)
+	for i := offset; i < end; i++ {
+		s := m.steps[i]
+		prefix := "  "
+		subject := s.Commit.Subject
+		if i == m.cursor {
+			prefix = styles.HighlightStyle.Render("> ")
+			subject = styles.HighlightStyle.Render(subject)
+		}
+		if s.Action == git.RebaseDrop {
+			subject = styles.SubtitleStyle.Strikethrough(true).Render(s.Commit.Subject)
+		}
+		action := actionStyle(s.Action).Render(fmt.Sprintf("%-6s", s.Action))
+		rows = append(rows, prefix+action+" "+styles.SubtitleStyle.Render(s.Commit.ShortHash)+" "+subject)
+	}
+	return strings.Join(rows, "\n")
+}
+
+func actionStyle(a git.RebaseAction) lipgloss.Style {
+	switch a {
+	case git.RebasePick:
+		return styles.BadgeSuccess
+	case git.RebaseReword, git.RebaseEdit:
+		return styles.BadgePending
+	case git.RebaseSquash, git.RebaseFixup:
+		return lipgloss.NewStyle().Foreground(styles.ColorPrimary).Bold(true)
+	default:
+		return styles.BadgeFailure
+	}
+}
+
+func (m Model) loadCommits() tea.Msg {
+	if m.onto == "" {
+		return commitsLoadedMsg{err: fmt.Errorf("no default branch to rebase against")}
+	}
+	base, err := m.repo.MergeBase(m.onto, "HEAD")
+	if err != nil {
+		return commitsLoadedMsg{err: err}
+	}
+	commits, err := m.repo.CommitsBetween(base, "HEAD")
+	return commitsLoadedMsg{base: base, commits: commits, err: err}
+}
+
+func runCombined(cmd *exec.Cmd) error {
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func emitRefreshReflog() tea.Msg {
+	return reflog.RefreshReflogMsg{}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -16,36 +17,85 @@ const reflogLimit = 50
 // RefreshReflogMsg is sent by other views to trigger a reflog reload.
 type RefreshReflogMsg struct{}
 
+// FocusMsg gives the reflog pane keyboard focus.
+type FocusMsg struct{}
+
+// UndoMsg asks the reflog pane to undo the last operation.
+type UndoMsg struct{}
+
+// ChangedMsg is sent after the reflog pane moved HEAD, so other views can
+// reload.
+type ChangedMsg struct{}
+
 type reflogLoadedMsg struct {
 	entries []git.ReflogEntry
 	err     error
 }
 
+type undoPlannedMsg struct {
+	plan git.UndoPlan
+	err  error
+}
+
+type restoreDoneMsg struct {
+	done string
+	err  error
+}
+
+type prompt int
+
+const (
+	promptNone prompt = iota
+	promptReset
+	promptBranch
+	promptUndo
+)
+
 type Model struct {
-	repo     *git.Repo
-	viewport viewport.Model
-	entries  []git.ReflogEntry
-	ready    bool
-	width    int
-	height   int
+	repo      *git.Repo
+	viewport  viewport.Model
+	entries   []git.ReflogEntry
+	ready     bool
+	width     int
+	height    int
+	focused   bool
+	cursor    int
+	prompt    prompt
+	resetMode git.ResetMode
+	target    git.ReflogEntry // entry a reset or branch prompt acts on
+	undoPlan  git.UndoPlan
+	input     textinput.Model
+	status    string
 }
 
 func New(repo *git.Repo) Model {
-	return Model{repo: repo}
+	ti := textinput.New()
+	ti.Prompt = "Branch name: "
+	ti.CharLimit = 128
+	return Model{repo: repo, input: ti}
 }
 
 func (m Model) Init() tea.Cmd {
 	return m.loadReflog
 }
 
+// IsActive reports whether the pane wants keyboard input.
+func (m Model) IsActive() bool {
+	return m.focused || m.prompt != promptNone
+}
+
+func (m Model) IsPrompting() bool {
+	return m.prompt != promptNone
+}
+
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		// Account for border (2) + title line with newline (1)
+		// Account for border (2) + title line (1) + status line (1)
 		innerWidth := msg.Width - 2
-		innerHeight := msg.Height - 3
+		innerHeight := msg.Height - 4
 		if innerWidth < 0 {
 			innerWidth = 0
 		}
@@ -70,14 +120,55 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			return m, nil
 		}
 		m.entries = msg.entries
+		if m.cursor >= len(m.entries) {
+			m.cursor = 0
+		}
 		if m.ready {
-			m.viewport.SetContent(m.renderEntries(m.viewport.Width))
 			m.viewport.GotoTop()
+			m.refresh()
 		}
 		return m, nil
 
 	case RefreshReflogMsg:
 		return m, m.loadReflog
+
+	case FocusMsg:
+		m.focused = true
+		m.status = ""
+		m.refresh()
+		return m, nil
+
+	case UndoMsg:
+		m.focused = true
+		m.status = styles.BadgePending.Render("Looking up last operation...")
+		return m, m.planUndo
+
+	case undoPlannedMsg:
+		if msg.err != nil {
+			m.status = styles.ErrorLineStyle.Render("Cannot undo: ") + styles.SubtitleStyle.Render(msg.err.Error())
+			return m, nil
+		}
+		m.undoPlan = msg.plan
+		m.prompt = promptUndo
+		m.status = ""
+		return m, nil
+
+	case restoreDoneMsg:
+		if msg.err != nil {
+			m.status = styles.ErrorLineStyle.Render("Failed: ") + styles.SubtitleStyle.Render(msg.err.Error())
+			return m, m.loadReflog
+		}
+		m.status = styles.BadgeSuccess.Render(msg.done)
+		m.cursor = 0
+		return m, tea.Batch(m.loadReflog, func() tea.Msg { return ChangedMsg{} })
+
+	case tea.KeyMsg:
+		if m.prompt != promptNone {
+			return m.handlePrompt(msg)
+		}
+		if m.focused {
+			return m.handleKey(msg)
+		}
 	}
 
 	if m.ready {
@@ -88,41 +179,163 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m Model) handleKey(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.focused = false
+		m.status = ""
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.entries)-1 {
+			m.cursor++
+		}
+	case "s", "m", "h":
+		if len(m.entries) == 0 {
+			return m, nil
+		}
+		m.resetMode = map[string]git.ResetMode{"s": git.ResetSoft, "m": git.ResetMixed, "h": git.ResetHard}[msg.String()]
+		m.target = m.entries[m.cursor]
+		m.prompt = promptReset
+		m.status = ""
+	case "b":
+		if len(m.entries) == 0 {
+			return m, nil
+		}
+		m.target = m.entries[m.cursor]
+		m.prompt = promptBranch
+		m.input.SetValue("")
+		m.input.Focus()
+		m.status = ""
+		return m, m.input.Cursor.BlinkCmd()
+	case "d":
+		if len(m.entries) == 0 {
+			return m, nil
+		}
+		e := m.entries[m.cursor]
+		m.status = styles.BadgePending.Render("Checking out " + e.Hash + "...")
+		return m, m.restore("Checked out "+e.Hash+" (detached)", func() error { return m.repo.CheckoutDetached(e.Commit) })
+	}
+	m.refresh()
+	return m, nil
+}
+
+func (m Model) handlePrompt(msg tea.KeyMsg) (Model, tea.Cmd) {
+	if m.prompt == promptBranch {
+		switch msg.String() {
+		case "esc":
+			m.prompt = promptNone
+			return m, nil
+		case "enter":
+			name := strings.TrimSpace(m.input.Value())
+			if name == "" {
+				return m, nil
+			}
+			m.prompt = promptNone
+			e := m.target
+			return m, m.restore("Created "+name+" at "+e.Hash, func() error { return m.repo.CreateBranchAt(name, e.Commit) })
+		}
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "y":
+		p := m.prompt
+		m.prompt = promptNone
+		if p == promptUndo {
+			plan := m.undoPlan
+			m.status = styles.BadgePending.Render("Undoing...")
+			return m, m.restore("Undone: "+plan.Description, func() error { return m.repo.Undo(plan) })
+		}
+		e, mode := m.target, m.resetMode
+		m.status = styles.BadgePending.Render("Resetting...")
+		return m, m.restore(fmt.Sprintf("Reset --%s to %s", mode, e.Hash), func() error { return m.repo.Reset(e.Commit, mode) })
+	case "n", "esc":
+		m.prompt = promptNone
+	}
+	return m, nil
+}
+
+// refresh re-renders the entries and keeps the cursor in view.
+func (m *Model) refresh() {
+	if !m.ready {
+		return
+	}
+	m.viewport.SetContent(m.renderEntries(m.viewport.Width))
+	if m.cursor < m.viewport.YOffset {
+		m.viewport.SetYOffset(m.cursor)
+	} else if m.cursor >= m.viewport.YOffset+m.viewport.Height {
+		m.viewport.SetYOffset(m.cursor - m.viewport.Height + 1)
+	}
+}
+
 func (m Model) View() string {
 	if !m.ready {
 		return ""
 	}
 
 	title := styles.SubtitleStyle.Render(styles.IconHistory + " Git Reflog")
+	borderColor := styles.ColorMuted
+	if m.IsActive() {
+		title = styles.HighlightStyle.Render(styles.IconHistory + " Git Reflog")
+		borderColor = styles.ColorPrimary
+	}
 
 	border := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(styles.ColorMuted).
+		BorderForeground(borderColor).
 		Width(m.width - 2).
 		Height(m.height - 2)
 
-	content := title + "\n" + m.viewport.View()
+	content := title + "\n" + m.viewport.View() + "\n" + m.footer()
 	return border.Render(content)
 }
 
+func (m Model) footer() string {
+	switch m.prompt {
+	case promptReset:
+		e := m.target
+		warn := ""
+		if m.resetMode == git.ResetHard {
+			warn = " Uncommitted changes will be lost!"
+		}
+		return styles.BadgePending.Render(fmt.Sprintf(" Reset --%s to %s (%s)?%s", m.resetMode, e.Selector, e.Hash, warn)) + styles.SubtitleStyle.Render(" y/n")
+	case promptUndo:
+		return styles.BadgePending.Render(" Undo "+m.undoPlan.Entry.Action+": "+m.undoPlan.Description+"?") + styles.SubtitleStyle.Render(" y/n")
+	case promptBranch:
+		return " " + m.input.View()
+	}
+	if m.status != "" {
+		return " " + m.status
+	}
+	return ""
+}
+
 func (m Model) renderEntries(width int) string {
 	if len(m.entries) == 0 {
 		return styles.SubtitleStyle.Render("  No reflog entries")
 	}
 
 	var b strings.Builder
-	for _, e := range m.entries {
-		line := m.renderEntry(e, width)
+	for i, e := range m.entries {
+		line := m.renderEntry(e, width, m.focused && i == m.cursor)
 		b.WriteString(line)
 		b.WriteByte('\n')
 	}
 	return b.String()
 }
 
-func (m Model) renderEntry(e git.ReflogEntry, width int) string {
+func (m Model) renderEntry(e git.ReflogEntry, width int, selected bool) string {
 	actionColor := actionStyle(e.Action)
 
 	selector := styles.SubtitleStyle.Render(e.Selector)
+	if selected {
+		selector = styles.HighlightStyle.Render(e.Selector)
+	}
 	action := actionColor.Render(e.Action)
 	timeAgo := styles.SubtitleStyle.Render(e.TimeAgo)
 
@@ -147,7 +360,11 @@ func (m Model) renderEntry(e git.ReflogEntry, width int) string {
 	}
 	parts = append(parts, timeAgo)
 
-	return fmt.Sprintf(" %s", strings.Join(parts, " "))
+	prefix := " "
+	if selected {
+		prefix = styles.HighlightStyle.Render(">")
+	}
+	return prefix + strings.Join(parts, " ")
 }
 
 func actionStyle(action string) lipgloss.Style {
@@ -169,3 +386,14 @@ func (m Model) loadReflog() tea.Msg {
 	entries, err := m.repo.GetReflog(reflogLimit)
 	return reflogLoadedMsg{entries: entries, err: err}
 }
+
+func (m Model) planUndo() tea.Msg {
+	plan, err := m.repo.PlanUndo()
+	return undoPlannedMsg{plan: plan, err: err}
+}
+
+func (m Model) restore(done string, fn func() error) tea.Cmd {
+	return func() tea.Msg {
+		return restoreDoneMsg{done: done, err: fn()}
+	}
+}
@@ -0,0 +1,55 @@
+package releases
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/elisa-content-delivery/hit/internal/git"
+	gh "github.com/elisa-content-delivery/hit/internal/github"
+	"github.com/elisa-content-delivery/hit/internal/styles"
+)
+
+// tagItem is a tag that exists locally, on the remote, or only as the tag
+// name of a (draft) release.
+type tagItem struct {
+	name    string
+	tag     *git.Tag
+	remote  bool
+	release *gh.Release
+}
+
+func (t tagItem) Title() string {
+	badges := []string{t.name}
+	if t.release != nil {
+		switch {
+		case t.release.Draft:
+			badges = append(badges, styles.BadgePending.Render("draft"))
+		case t.release.Prerelease:
+			badges = append(badges, styles.BadgePending.Render("prerelease"))
+		default:
+			badges = append(badges, styles.BadgeSuccess.Render("release"))
+		}
+	}
+	return strings.Join(badges, " ")
+}
+
+func (t tagItem) Description() string {
+	var parts []string
+	if t.tag != nil {
+		parts = append(parts, styles.SubtitleStyle.Render(t.tag.Hash)+" "+t.tag.Subject)
+	}
+	switch {
+	case t.tag != nil && t.remote:
+		parts = append(parts, styles.BadgeSuccess.Render(styles.IconCloud+" pushed"))
+	case t.tag != nil:
+		parts = append(parts, styles.SubtitleStyle.Render(styles.IconLocal+" local"))
+	case t.remote:
+		parts = append(parts, styles.SubtitleStyle.Render(styles.IconCloud+" remote only"))
+	}
+	if t.release != nil && len(t.release.Assets) > 0 {
+		parts = append(parts, fmt.Sprintf("%d assets", len(t.release.Assets)))
+	}
+	return strings.Join(parts, " · ")
+}
+
+func (t tagItem) FilterValue() string { return t.name }
@@ -0,0 +1,411 @@
+package releases
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/elisa-content-delivery/hit/internal/git"
+	gh "github.com/elisa-content-delivery/hit/internal/github"
+	"github.com/elisa-content-delivery/hit/internal/styles"
+)
+
+type tagsLoadedMsg struct {
+	tags []git.Tag
+	err  error
+}
+
+type remoteTagsLoadedMsg struct {
+	names map[string]bool
+	err   error
+}
+
+type releasesLoadedMsg struct {
+	releases []gh.Release
+	err      error
+}
+
+type actionDoneMsg struct {
+	action string
+	name   string
+	err    error
+}
+
+type inputStep int
+
+const (
+	inputNone inputStep = iota
+	inputName
+	inputRev
+	inputMessage
+)
+
+type Model struct {
+	repo       *git.Repo
+	client     *gh.Client
+	list       list.Model
+	detail     viewport.Model
+	detailFor  string
+	input      textinput.Model
+	step       inputStep
+	newTag     string
+	newRev     string
+	tags       []git.Tag
+	remoteTags map[string]bool
+	releases   []gh.Release
+	width      int
+	height     int
+	status     string
+}
+
+// New builds the view. client may be nil, in which case only git tags are
+// shown.
+func New(repo *git.Repo, client *gh.Client) Model {
+	l := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	l.Title = "Tags & Releases"
+	l.SetShowHelp(false)
+	l.SetFilteringEnabled(true)
+	l.SetStatusBarItemName("tag", "tags")
+	l.Styles.Title = styles.TitleStyle
+
+	vp := viewport.New(0, 0)
+	vp.HighPerformanceRendering = false
+
+	ti := textinput.New()
+	ti.CharLimit = 200
+
+	return Model{repo: repo, client: client, list: l, detail: vp, input: ti}
+}
+
+func (m Model) Init() tea.Cmd {
+	cmds := []tea.Cmd{m.loadTags, m.loadRemoteTags}
+	if m.client != nil {
+		cmds = append(cmds, m.loadReleases)
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m Model) IsInputActive() bool {
+	return m.step != inputNone || m.list.FilterState() == list.Filtering
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.list.SetSize(m.listWidth(), msg.Height-4)
+		m.detail.Width = max(msg.Width-m.listWidth()-3, 0)
+		m.detail.Height = max(msg.Height-3, 0)
+		m.detailFor = ""
+		m.renderDetail()
+		return m, nil
+
+	case tagsLoadedMsg:
+		if msg.err != nil {
+			m.status = styles.ErrorLineStyle.Render("Error: ") + styles.SubtitleStyle.Render(msg.err.Error())
+			return m, nil
+		}
+		m.tags = msg.tags
+		m.rebuild()
+		return m, nil
+
+	case remoteTagsLoadedMsg:
+		if msg.err != nil {
+			m.status = styles.ErrorLineStyle.Render("Remote tags unavailable: ") + styles.SubtitleStyle.Render(msg.err.Error())
+			return m, nil
+		}
+		m.remoteTags = msg.names
+		m.rebuild()
+		return m, nil
+
+	case releasesLoadedMsg:
+		if msg.err != nil {
+			m.status = styles.ErrorLineStyle.Render("Error: ") + styles.SubtitleStyle.Render(msg.err.Error())
+			return m, nil
+		}
+		m.releases = msg.releases
+		m.rebuild()
+		return m, nil
+
+	case actionDoneMsg:
+		if msg.err != nil {
+			m.status = styles.ErrorLineStyle.Render(msg.action+" failed: ") + styles.SubtitleStyle.Render(msg.err.Error())
+			return m, nil
+		}
+		m.status = styles.BadgeSuccess.Render(msg.action+" ") + styles.HighlightStyle.Render(msg.name)
+		return m, m.Init()
+
+	case tea.KeyMsg:
+		if m.step != inputNone {
+			return m.handleInput(msg)
+		}
+		if m.list.FilterState() == list.Filtering {
+			break
+		}
+
+		switch msg.String() {
+		case "t":
+			m.prompt(inputName, "Tag name: ", "")
+			return m, m.input.Cursor.BlinkCmd()
+
+		case "p":
+			item, ok := m.selected()
+			if !ok || item.tag == nil {
+				return m, nil
+			}
+			name := item.name
+			m.status = styles.BadgePending.Render("Pushing " + name + "...")
+			return m, func() tea.Msg {
+				remote, err := m.repo.PushTag(name)
+				return actionDoneMsg{action: "Pushed to " + remote, name: name, err: err}
+			}
+
+		case "n":
+			item, ok := m.selected()
+			if !ok || m.client == nil {
+				return m, nil
+			}
+			if item.release != nil {
+				m.status = styles.SubtitleStyle.Render(item.name + " already has a release")
+				return m, nil
+			}
+			if !item.remote {
+				m.status = styles.ErrorLineStyle.Render("Push the tag first: ") + styles.SubtitleStyle.Render("p pushes "+item.name)
+				return m, nil
+			}
+			name := item.name
+			m.status = styles.BadgePending.Render("Drafting release " + name + "...")
+			return m, func() tea.Msg {
+				_, err := m.client.DraftRelease(name, m.repo.PreviousTag(name))
+				return actionDoneMsg{action: "Drafted release", name: name, err: err}
+			}
+
+		case "r":
+			m.status = ""
+			return m, m.Init()
+
+		case "pgup", "pgdown":
+			var cmd tea.Cmd
+			m.detail, cmd = m.detail.Update(msg)
+			return m, cmd
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	m.renderDetail()
+	return m, cmd
+}
+
+func (m *Model) prompt(step inputStep, prompt, value string) {
+	m.step = step
+	m.input.Prompt = prompt
+	m.input.SetValue(value)
+	m.input.CursorEnd()
+	m.input.Focus()
+	m.status = ""
+}
+
+func (m Model) handleInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.step = inputNone
+		return m, nil
+	case "enter":
+		value := strings.TrimSpace(m.input.Value())
+		switch m.step {
+		case inputName:
+			if value == "" {
+				return m, nil
+			}
+			m.newTag = value
+			m.prompt(inputRev, "Commit: ", "HEAD")
+			return m, nil
+		case inputRev:
+			if value == "" {
+				return m, nil
+			}
+			m.newRev = value
+			m.prompt(inputMessage, "Message: ", m.newTag)
+			return m, nil
+		}
+
+		m.step = inputNone
+		name, rev, message := m.newTag, m.newRev, value
+		if message == "" {
+			message = name
+		}
+		m.status = styles.BadgePending.Render("Tagging " + rev + " as " + name + "...")
+		return m, func() tea.Msg {
+			return actionDoneMsg{action: "Created tag", name: name, err: m.repo.CreateTag(name, rev, message)}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// rebuild merges local tags, remote tag names and releases into one list.
+func (m *Model) rebuild() {
+	byName := make(map[string]*tagItem)
+	var order []string
+	add := func(name string) *tagItem {
+		if item, ok := byName[name]; ok {
+			return item
+		}
+		byName[name] = &tagItem{name: name}
+		order = append(order, name)
+		return byName[name]
+	}
+
+	// Drafts first, then tags newest first, then anything only known remotely
+	for i := range m.releases {
+		if m.releases[i].Draft {
+			add(m.releases[i].TagName).release = &m.releases[i]
+		}
+	}
+	for i := range m.tags {
+		add(m.tags[i].Name).tag = &m.tags[i]
+	}
+	for i := range m.releases {
+		if !m.releases[i].Draft {
+			add(m.releases[i].TagName).release = &m.releases[i]
+		}
+	}
+	var remoteOnly []string
+	for name := range m.remoteTags {
+		if _, ok := byName[name]; !ok {
+			remoteOnly = append(remoteOnly, name)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(remoteOnly)))
+	for _, name := range remoteOnly {
+		add(name)
+	}
+
+	items := make([]list.Item, len(order))
+	for i, name := range order {
+		item := byName[name]
+		item.remote = m.remoteTags[name]
+		items[i] = *item
+	}
+	m.list.SetItems(items)
+	m.detailFor = ""
+	m.renderDetail()
+}
+
+// renderDetail shows the selected tag, keeping the scroll position while the
+// selection doesn't change.
+func (m *Model) renderDetail() {
+	item, ok := m.selected()
+	if !ok {
+		m.detail.SetContent(styles.SubtitleStyle.Render("No tags"))
+		return
+	}
+	if item.name == m.detailFor {
+		return
+	}
+	m.detailFor = item.name
+
+	var b strings.Builder
+	if item.tag != nil {
+		kind := "lightweight"
+		if item.tag.Annotated {
+			kind = "annotated"
+		}
+		fmt.Fprintf(&b, "%s %s · %s\n", styles.SubtitleStyle.Render(item.tag.Hash), kind, item.tag.When.Format("Jan 02 2006 15:04"))
+		fmt.Fprintf(&b, "%s\n\n", item.tag.Subject)
+	}
+	if item.release == nil {
+		if m.client == nil {
+			b.WriteString(styles.SubtitleStyle.Render("Not signed in to GitHub"))
+		} else {
+			b.WriteString(styles.SubtitleStyle.Render("No GitHub release"))
+		}
+		m.detail.SetContent(b.String())
+		m.detail.GotoTop()
+		return
+	}
+
+	r := item.release
+	title := r.Name
+	if title == "" {
+		title = r.TagName
+	}
+	b.WriteString(styles.TitleStyle.Render(title) + "\n")
+	b.WriteString(styles.SubtitleStyle.Render(r.HTMLURL) + "\n\n")
+	if len(r.Assets) > 0 {
+		b.WriteString(styles.HighlightStyle.Render("Assets") + "\n")
+		for _, a := range r.Assets {
+			fmt.Fprintf(&b, "  %s %s · %d downloads\n", a.Name, styles.SubtitleStyle.Render(formatSize(a.Size)), a.DownloadCount)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(r.Body)
+	m.detail.SetContent(lipgloss.NewStyle().Width(m.detail.Width).Render(b.String()))
+	m.detail.GotoTop()
+}
+
+func (m Model) View() string {
+	detail := lipgloss.NewStyle().
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderLeft(true).
+		BorderForeground(styles.ColorMuted).
+		PaddingLeft(1).
+		Render(m.detail.View())
+
+	left := lipgloss.NewStyle().Width(m.listWidth()).Render(m.list.View())
+	content := lipgloss.JoinHorizontal(lipgloss.Top, left, detail)
+
+	if m.step != inputNone {
+		content += "\n" + lipgloss.NewStyle().MarginLeft(2).Render(m.input.View())
+	} else if m.status != "" {
+		content += "\n" + lipgloss.NewStyle().MarginLeft(2).Render(m.status)
+	}
+	return content
+}
+
+func (m Model) listWidth() int {
+	return max(m.width*2/5, min(40, m.width))
+}
+
+func (m Model) selected() (tagItem, bool) {
+	item, ok := m.list.SelectedItem().(tagItem)
+	return item, ok
+}
+
+func (m Model) loadTags() tea.Msg {
+	tags, err := m.repo.ListTags()
+	return tagsLoadedMsg{tags: tags, err: err}
+}
+
+func (m Model) loadRemoteTags() tea.Msg {
+	remote := m.repo.CanonicalRemote()
+	if remote == "" {
+		return remoteTagsLoadedMsg{}
+	}
+	names, err := m.repo.RemoteTags(remote)
+	return remoteTagsLoadedMsg{names: names, err: err}
+}
+
+func (m Model) loadReleases() tea.Msg {
+	releases, err := m.client.GetReleases()
+	return releasesLoadedMsg{releases: releases, err: err}
+}
+
+func formatSize(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
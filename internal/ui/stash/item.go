@@ -0,0 +1,24 @@
+package stash
+
+import (
+	"fmt"
+
+	"github.com/elisa-content-delivery/hit/internal/git"
+	"github.com/elisa-content-delivery/hit/internal/styles"
+)
+
+type stashItem struct{ stash git.Stash }
+
+func (s stashItem) Title() string {
+	return styles.SubtitleStyle.Render(s.stash.Ref) + " " + s.stash.Message
+}
+
+func (s stashItem) Description() string {
+	files := "1 file"
+	if s.stash.Files != 1 {
+		files = fmt.Sprintf("%d files", s.stash.Files)
+	}
+	return styles.HighlightStyle.Render(styles.IconBranch+" "+s.stash.Branch) + " · " + s.stash.TimeAgo + " · " + files
+}
+
+func (s stashItem) FilterValue() string { return s.stash.Message }
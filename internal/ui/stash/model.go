@@ -0,0 +1,262 @@
+package stash
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/elisa-content-delivery/hit/internal/git"
+	"github.com/elisa-content-delivery/hit/internal/styles"
+)
+
+type stashesLoadedMsg struct {
+	stashes []git.Stash
+	err     error
+}
+
+type diffLoadedMsg struct {
+	ref  string
+	diff string
+	err  error
+}
+
+type actionDoneMsg struct {
+	action string
+	ref    string
+	err    error
+}
+
+type Model struct {
+	repo        *git.Repo
+	list        list.Model
+	diffView    viewport.Model
+	diffRef     string
+	msgInput    textinput.Model
+	creating    bool
+	confirmDrop bool
+	width       int
+	height      int
+	status      string
+}
+
+func New(repo *git.Repo) Model {
+	l := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	l.Title = "Stashes"
+	l.SetShowHelp(false)
+	l.SetFilteringEnabled(true)
+	l.SetStatusBarItemName("stash", "stashes")
+	l.Styles.Title = styles.TitleStyle
+
+	vp := viewport.New(0, 0)
+	vp.HighPerformanceRendering = false
+
+	ti := textinput.New()
+	ti.Prompt = "Stash message: "
+	ti.CharLimit = 200
+
+	return Model{repo: repo, list: l, diffView: vp, msgInput: ti}
+}
+
+func (m Model) Init() tea.Cmd {
+	return m.loadStashes
+}
+
+func (m Model) IsInputActive() bool {
+	return m.creating || m.confirmDrop || m.list.FilterState() == list.Filtering
+}
+
+func (m Model) IsConfirming() bool {
+	return m.confirmDrop
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.list.SetSize(m.listWidth(), msg.Height-4)
+		m.diffView.Width = max(msg.Width-m.listWidth()-3, 0)
+		m.diffView.Height = max(msg.Height-3, 0)
+		return m, nil
+
+	case stashesLoadedMsg:
+		if msg.err != nil {
+			m.status = styles.ErrorLineStyle.Render("Error: ") + styles.SubtitleStyle.Render(msg.err.Error())
+			return m, nil
+		}
+		items := make([]list.Item, len(msg.stashes))
+		for i, s := range msg.stashes {
+			items[i] = stashItem{stash: s}
+		}
+		m.list.SetItems(items)
+		m.diffRef = ""
+		if len(items) == 0 {
+			m.diffView.SetContent(styles.SubtitleStyle.Render("No stashes"))
+		}
+		return m, m.loadSelectedDiff()
+
+	case diffLoadedMsg:
+		if msg.ref != m.selectedRef() {
+			return m, nil
+		}
+		m.diffRef = msg.ref
+		if msg.err != nil {
+			m.diffView.SetContent(styles.ErrorLineStyle.Render(msg.err.Error()))
+		} else {
+			m.diffView.SetContent(styles.Diff(msg.diff))
+		}
+		m.diffView.GotoTop()
+		return m, nil
+
+	case actionDoneMsg:
+		if msg.err != nil {
+			m.status = styles.ErrorLineStyle.Render(msg.action+" failed: ") + styles.SubtitleStyle.Render(msg.err.Error())
+			return m, m.loadStashes
+		}
+		m.status = styles.BadgeSuccess.Render(msg.action+" ") + styles.HighlightStyle.Render(msg.ref)
+		return m, m.loadStashes
+
+	case tea.KeyMsg:
+		if m.confirmDrop {
+			return m.handleConfirmDrop(msg)
+		}
+		if m.creating {
+			return m.handleCreateInput(msg)
+		}
+		if m.list.FilterState() == list.Filtering {
+			var cmd tea.Cmd
+			m.list, cmd = m.list.Update(msg)
+			return m, tea.Batch(cmd, m.loadSelectedDiff())
+		}
+
+		switch msg.String() {
+		case "a", "p":
+			ref := m.selectedRef()
+			if ref == "" {
+				return m, nil
+			}
+			if msg.String() == "a" {
+				m.status = styles.BadgePending.Render("Applying " + ref + "...")
+				return m, m.runAction("Applied", ref, m.repo.StashApply)
+			}
+			m.status = styles.BadgePending.Render("Popping " + ref + "...")
+			return m, m.runAction("Popped", ref, m.repo.StashPop)
+
+		case "d":
+			if m.selectedRef() != "" {
+				m.confirmDrop = true
+			}
+			return m, nil
+
+		case "s":
+			m.creating = true
+			m.msgInput.SetValue("")
+			m.msgInput.Focus()
+			m.status = ""
+			return m, m.msgInput.Cursor.BlinkCmd()
+
+		case "r":
+			m.status = ""
+			return m, m.loadStashes
+
+		case "pgup", "pgdown":
+			var cmd tea.Cmd
+			m.diffView, cmd = m.diffView.Update(msg)
+			return m, cmd
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, tea.Batch(cmd, m.loadSelectedDiff())
+}
+
+func (m Model) handleConfirmDrop(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "y":
+		m.confirmDrop = false
+		ref := m.selectedRef()
+		m.status = styles.BadgePending.Render("Dropping " + ref + "...")
+		return m, m.runAction("Dropped", ref, m.repo.StashDrop)
+	case "n", "esc":
+		m.confirmDrop = false
+	}
+	return m, nil
+}
+
+func (m Model) handleCreateInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.creating = false
+		return m, nil
+	case "enter":
+		m.creating = false
+		message := m.msgInput.Value()
+		m.status = styles.BadgePending.Render("Stashing changes...")
+		return m, m.runAction("Stashed", "stash@{0}", func(string) error {
+			return m.repo.StashPush(message, true)
+		})
+	}
+
+	var cmd tea.Cmd
+	m.msgInput, cmd = m.msgInput.Update(msg)
+	return m, cmd
+}
+
+func (m Model) View() string {
+	diff := lipgloss.NewStyle().
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderLeft(true).
+		BorderForeground(styles.ColorMuted).
+		PaddingLeft(1).
+		Render(styles.TitleStyle.Render("Diff") + " " + styles.SubtitleStyle.Render(m.diffRef) + "\n" + m.diffView.View())
+
+	left := lipgloss.NewStyle().Width(m.listWidth()).Render(m.list.View())
+	content := lipgloss.JoinHorizontal(lipgloss.Top, left, diff)
+
+	if m.creating {
+		content += "\n" + lipgloss.NewStyle().MarginLeft(2).Render(m.msgInput.View())
+	}
+	if m.confirmDrop {
+		content += "\n" + lipgloss.NewStyle().MarginLeft(2).Render(
+			styles.BadgePending.Render("Drop "+m.selectedRef()+"? ")+styles.SubtitleStyle.Render("This cannot be undone. (y/n)"))
+	} else if m.status != "" {
+		content += "\n" + lipgloss.NewStyle().MarginLeft(2).Render(m.status)
+	}
+	return content
+}
+
+func (m Model) listWidth() int {
+	return max(m.width*2/5, min(40, m.width))
+}
+
+func (m Model) selectedRef() string {
+	selected, ok := m.list.SelectedItem().(stashItem)
+	if !ok {
+		return ""
+	}
+	return selected.stash.Ref
+}
+
+func (m Model) loadStashes() tea.Msg {
+	stashes, err := m.repo.ListStashes()
+	return stashesLoadedMsg{stashes: stashes, err: err}
+}
+
+func (m Model) loadSelectedDiff() tea.Cmd {
+	ref := m.selectedRef()
+	if ref == "" || ref == m.diffRef {
+		return nil
+	}
+	return func() tea.Msg {
+		diff, err := m.repo.StashDiff(ref)
+		return diffLoadedMsg{ref: ref, diff: diff, err: err}
+	}
+}
+
+func (m Model) runAction(name, ref string, fn func(string) error) tea.Cmd {
+	return func() tea.Msg {
+		return actionDoneMsg{action: name, ref: ref, err: fn(ref)}
+	}
+}
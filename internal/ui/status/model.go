@@ -0,0 +1,416 @@
+package status
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/elisa-content-delivery/hit/internal/git"
+	"github.com/elisa-content-delivery/hit/internal/styles"
+	"github.com/elisa-content-delivery/hit/internal/ui/commit"
+)
+
+type focus int
+
+const (
+	focusFiles focus = iota
+	focusDiff
+)
+
+type statusLoadedMsg struct {
+	entries []git.StatusEntry
+	err     error
+}
+
+type diffLoadedMsg struct {
+	entry git.StatusEntry
+	diff  string
+	err   error
+}
+
+type actionDoneMsg struct {
+	action string
+	err    error
+}
+
+type Model struct {
+	repo           *git.Repo
+	entries        []git.StatusEntry
+	cursor         int
+	focus          focus
+	patch          git.Patch
+	hunk           int
+	diffView       viewport.Model
+	confirmDiscard bool
+	width          int
+	height         int
+	status         string
+}
+
+func New(repo *git.Repo) Model {
+	vp := viewport.New(0, 0)
+	vp.HighPerformanceRendering = false
+	return Model{repo: repo, diffView: vp}
+}
+
+func (m Model) Init() tea.Cmd {
+	return m.loadStatus
+}
+
+func (m Model) IsConfirming() bool {
+	return m.confirmDiscard
+}
+
+func (m Model) IsDiffFocused() bool {
+	return m.focus == focusDiff
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.diffView.Width = m.diffWidth()
+		m.diffView.Height = max(msg.Height-3, 0)
+		m.renderDiff()
+		return m, nil
+
+	case statusLoadedMsg:
+		if msg.err != nil {
+			m.status = styles.ErrorLineStyle.Render("Error: ") + styles.SubtitleStyle.Render(msg.err.Error())
+			return m, nil
+		}
+		sort.SliceStable(msg.entries, func(i, j int) bool {
+			return msg.entries[i].Section < msg.entries[j].Section
+		})
+		m.entries = msg.entries
+		if m.cursor >= len(m.entries) {
+			m.cursor = max(len(m.entries)-1, 0)
+		}
+		if len(m.entries) == 0 {
+			m.focus = focusFiles
+			m.patch = git.Patch{}
+			m.renderDiff()
+			return m, nil
+		}
+		return m, m.loadDiff(m.entries[m.cursor])
+
+	case diffLoadedMsg:
+		sel, ok := m.selected()
+		if !ok || sel != msg.entry {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.patch = git.Patch{}
+			m.status = styles.ErrorLineStyle.Render("Diff failed: ") + styles.SubtitleStyle.Render(msg.err.Error())
+		} else {
+			m.patch = git.ParsePatch(msg.diff)
+		}
+		if m.hunk >= len(m.patch.Hunks) {
+			m.hunk = max(len(m.patch.Hunks)-1, 0)
+		}
+		if len(m.patch.Hunks) == 0 {
+			m.focus = focusFiles
+		}
+		m.renderDiff()
+		return m, nil
+
+	case actionDoneMsg:
+		if msg.err != nil {
+			m.status = styles.ErrorLineStyle.Render(msg.action+" failed: ") + styles.SubtitleStyle.Render(msg.err.Error())
+		} else {
+			m.status = ""
+		}
+		return m, m.loadStatus
+
+	case tea.KeyMsg:
+		if m.confirmDiscard {
+			return m.handleConfirmDiscard(msg)
+		}
+		if m.focus == focusDiff {
+			return m.handleDiffKey(msg)
+		}
+		return m.handleFilesKey(msg)
+	}
+	return m, nil
+}
+
+func (m Model) handleFilesKey(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+			m.hunk = 0
+			return m, m.loadDiff(m.entries[m.cursor])
+		}
+	case "down", "j":
+		if m.cursor < len(m.entries)-1 {
+			m.cursor++
+			m.hunk = 0
+			return m, m.loadDiff(m.entries[m.cursor])
+		}
+	case "enter":
+		if len(m.patch.Hunks) > 0 {
+			m.focus = focusDiff
+			m.renderDiff()
+		}
+	case " ":
+		sel, ok := m.selected()
+		if !ok {
+			return m, nil
+		}
+		if sel.Section == git.SectionStaged {
+			return m, m.runAction("Unstage", func() error { return m.repo.UnstageFile(sel.Path) })
+		}
+		return m, m.runAction("Stage", func() error { return m.repo.StageFile(sel.Path) })
+	case "a":
+		return m, m.runAction("Stage all", m.repo.StageAll)
+	case "c":
+		return m, func() tea.Msg { return commit.OpenMsg{} }
+	case "d":
+		if sel, ok := m.selected(); ok && sel.Section != git.SectionConflicted {
+			m.confirmDiscard = true
+		}
+	case "r":
+		m.status = ""
+		return m, m.loadStatus
+	case "pgup", "pgdown":
+		var cmd tea.Cmd
+		m.diffView, cmd = m.diffView.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m Model) handleDiffKey(msg tea.KeyMsg) (Model, tea.Cmd) {
+	sel, ok := m.selected()
+	if !ok {
+		m.focus = focusFiles
+		return m, nil
+	}
+	switch msg.String() {
+	case "esc":
+		m.focus = focusFiles
+		m.renderDiff()
+	case "up", "k":
+		if m.hunk > 0 {
+			m.hunk--
+			m.renderDiff()
+		}
+	case "down", "j":
+		if m.hunk < len(m.patch.Hunks)-1 {
+			m.hunk++
+			m.renderDiff()
+		}
+	case " ":
+		patch, hunk := m.patch, m.hunk
+		switch sel.Section {
+		case git.SectionStaged:
+			return m, m.runAction("Unstage hunk", func() error { return m.repo.UnstageHunk(patch, hunk) })
+		case git.SectionUnstaged:
+			return m, m.runAction("Stage hunk", func() error { return m.repo.StageHunk(patch, hunk) })
+		default:
+			return m, m.runAction("Stage", func() error { return m.repo.StageFile(sel.Path) })
+		}
+	case "d":
+		if sel.Section == git.SectionUnstaged {
+			m.confirmDiscard = true
+		}
+	case "pgup", "pgdown":
+		var cmd tea.Cmd
+		m.diffView, cmd = m.diffView.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m Model) handleConfirmDiscard(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "y":
+		m.confirmDiscard = false
+		sel, ok := m.selected()
+		if !ok {
+			return m, nil
+		}
+		if m.focus == focusDiff {
+			patch, hunk := m.patch, m.hunk
+			return m, m.runAction("Discard hunk", func() error { return m.repo.DiscardHunk(patch, hunk) })
+		}
+		return m, m.runAction("Discard", func() error { return m.repo.DiscardFile(sel) })
+	case "n", "esc":
+		m.confirmDiscard = false
+	}
+	return m, nil
+}
+
+func (m Model) View() string {
+	files := m.renderFiles()
+	diff := lipgloss.NewStyle().
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderLeft(true).
+		BorderForeground(styles.ColorMuted).
+		PaddingLeft(1).
+		Render(m.diffTitle() + "\n" + m.diffView.View())
+
+	content := lipgloss.JoinHorizontal(lipgloss.Top, files, diff)
+	if m.confirmDiscard {
+		what := "all changes to this file"
+		if m.focus == focusDiff {
+			what = "the selected hunk"
+		}
+		content += "\n" + lipgloss.NewStyle().MarginLeft(2).Render(
+			styles.BadgePending.Render("Discard "+what+"? ")+styles.SubtitleStyle.Render("This cannot be undone. (y/n)"))
+	} else if m.status != "" {
+		content += "\n" + lipgloss.NewStyle().MarginLeft(2).Render(m.status)
+	}
+	return content
+}
+
+func (m Model) filesWidth() int {
+	w := m.width * 2 / 5
+	if w < 30 {
+		w = min(30, m.width)
+	}
+	return w
+}
+
+func (m Model) diffWidth() int {
+	return max(m.width-m.filesWidth()-3, 0)
+}
+
+func (m Model) renderFiles() string {
+	width := m.filesWidth()
+	title := styles.TitleStyle.Render("Working Tree")
+	if len(m.entries) == 0 {
+		body := title + "\n\n" + styles.SubtitleStyle.Render("  Nothing to commit, working tree clean")
+		return lipgloss.NewStyle().Width(width).Render(body)
+	}
+
+	var rows []string
+	cursorRow := 0
+	section := git.Section(-1)
+	for i, e := range m.entries {
+		if e.Section != section {
+			section = e.Section
+			rows = append(rows, sectionStyle(section).Render(section.String()))
+		}
+		if i == m.cursor {
+			cursorRow = len(rows)
+		}
+		rows = append(rows, m.renderEntry(e, i == m.cursor, width))
+	}
+
+	visible := max(m.height-3, 1)
+	offset := 0
+	if cursorRow >= visible {
+		offset = cursorRow - visible + 1
+	}
+	end := min(offset+visible, len(rows))
+	body := title + "\n" + strings.Join(rows[offset:end], "\n")
+	return lipgloss.NewStyle().Width(width).Render(body)
+}
+
+func (m Model) renderEntry(e git.StatusEntry, selected bool, width int) string {
+	path := e.Path
+	if e.OrigPath != "" {
+		path = e.OrigPath + " → " + e.Path
+	}
+	if limit := width - 6; limit > 3 && len(path) > limit {
+		path = "..." + path[len(path)-limit+3:]
+	}
+	code := sectionStyle(e.Section).Render(string(e.Code))
+	prefix := "  "
+	if selected {
+		prefix = styles.HighlightStyle.Render("> ")
+		if m.focus == focusFiles {
+			path = styles.HighlightStyle.Render(path)
+		}
+	}
+	return fmt.Sprintf("%s%s %s", prefix, code, path)
+}
+
+func sectionStyle(s git.Section) lipgloss.Style {
+	switch s {
+	case git.SectionConflicted:
+		return styles.BadgeFailure
+	case git.SectionStaged:
+		return styles.BadgeSuccess
+	case git.SectionUnstaged:
+		return styles.BadgePending
+	default:
+		return styles.BadgeNeutral
+	}
+}
+
+func (m Model) diffTitle() string {
+	sel, ok := m.selected()
+	if !ok {
+		return styles.TitleStyle.Render("Diff")
+	}
+	title := styles.TitleStyle.Render("Diff") + " " + styles.SubtitleStyle.Render(sel.Path)
+	if n := len(m.patch.Hunks); n > 0 && m.focus == focusDiff {
+		title += styles.HighlightStyle.Render(fmt.Sprintf("  hunk %d/%d", m.hunk+1, n))
+	}
+	return title
+}
+
+func (m *Model) renderDiff() {
+	if len(m.patch.Hunks) == 0 && len(m.patch.Header) == 0 {
+		m.diffView.SetContent(styles.SubtitleStyle.Render("No changes to show"))
+		return
+	}
+
+	marker := styles.HighlightStyle.Render("▌")
+
+	var b strings.Builder
+	hunkLine := 0
+	line := 0
+	for i, h := range m.patch.Hunks {
+		active := m.focus == focusDiff && i == m.hunk
+		prefix := " "
+		if active {
+			prefix = marker
+			hunkLine = line
+		}
+		b.WriteString(prefix + styles.HighlightStyle.Render(h.Header) + "\n")
+		line++
+		for _, l := range h.Lines {
+			b.WriteString(prefix + styles.DiffLine(l) + "\n")
+			line++
+		}
+	}
+	m.diffView.SetContent(b.String())
+	if m.focus == focusDiff {
+		m.diffView.SetYOffset(hunkLine)
+	} else {
+		m.diffView.GotoTop()
+	}
+}
+
+func (m Model) selected() (git.StatusEntry, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.entries) {
+		return git.StatusEntry{}, false
+	}
+	return m.entries[m.cursor], true
+}
+
+func (m Model) loadStatus() tea.Msg {
+	entries, err := m.repo.Status()
+	return statusLoadedMsg{entries: entries, err: err}
+}
+
+func (m Model) loadDiff(e git.StatusEntry) tea.Cmd {
+	return func() tea.Msg {
+		diff, err := m.repo.Diff(e)
+		return diffLoadedMsg{entry: e, diff: diff, err: err}
+	}
+}
+
+func (m Model) runAction(name string, fn func() error) tea.Cmd {
+	return func() tea.Msg {
+		return actionDoneMsg{action: name, err: fn()}
+	}
+}
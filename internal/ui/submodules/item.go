@@ -0,0 +1,48 @@
+package submodules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/elisa-content-delivery/hit/internal/git"
+	"github.com/elisa-content-delivery/hit/internal/styles"
+)
+
+type submoduleItem struct{ sub git.Submodule }
+
+func (s submoduleItem) Title() string {
+	prefix := styles.BadgeSuccess.Render(styles.IconCheck + " ")
+	if s.sub.NeedsAttention() {
+		prefix = styles.BadgePending.Render(styles.IconPending + " ")
+	}
+	return prefix + s.sub.Path
+}
+
+func (s submoduleItem) Description() string {
+	var parts []string
+	switch s.sub.State {
+	case git.SubmoduleUninitialized:
+		parts = append(parts, styles.BadgePending.Render("not initialized"))
+	case git.SubmoduleConflict:
+		parts = append(parts, styles.ErrorLineStyle.Render("merge conflict"))
+	case git.SubmoduleOutOfDate:
+		drift := "at " + s.sub.Current + ", recorded " + s.sub.Recorded
+		switch {
+		case s.sub.Ahead > 0 && s.sub.Behind > 0:
+			drift += fmt.Sprintf(" (%s%d%s%d)", styles.IconArrowUp, s.sub.Ahead, styles.IconArrowDn, s.sub.Behind)
+		case s.sub.Behind > 0:
+			drift += fmt.Sprintf(" (%d behind)", s.sub.Behind)
+		case s.sub.Ahead > 0:
+			drift += fmt.Sprintf(" (%d ahead)", s.sub.Ahead)
+		}
+		parts = append(parts, styles.BadgePending.Render("out of date")+" "+styles.SubtitleStyle.Render(drift))
+	default:
+		parts = append(parts, styles.SubtitleStyle.Render("at "+s.sub.Current))
+	}
+	if s.sub.Dirty {
+		parts = append(parts, styles.BadgePending.Render(styles.IconEdit+" dirty"))
+	}
+	return strings.Join(parts, " · ")
+}
+
+func (s submoduleItem) FilterValue() string { return s.sub.Path }
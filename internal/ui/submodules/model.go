@@ -0,0 +1,166 @@
+package submodules
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/elisa-content-delivery/hit/internal/git"
+	"github.com/elisa-content-delivery/hit/internal/styles"
+	"github.com/elisa-content-delivery/hit/internal/ui/worktrees"
+)
+
+// OpenMsg asks the app to show the submodules pane.
+type OpenMsg struct{}
+
+// CloseMsg returns to the view the pane was opened from.
+type CloseMsg struct{}
+
+type submodulesLoadedMsg struct {
+	subs []git.Submodule
+	err  error
+}
+
+type updateDoneMsg struct {
+	path string
+	err  error
+}
+
+type Model struct {
+	repo     *git.Repo
+	list     list.Model
+	updating bool
+	width    int
+	height   int
+	status   string
+}
+
+func New(repo *git.Repo) Model {
+	l := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	l.Title = "Submodules"
+	l.SetShowHelp(false)
+	l.SetFilteringEnabled(false)
+	l.SetStatusBarItemName("submodule", "submodules")
+	l.Styles.Title = styles.TitleStyle
+
+	return Model{repo: repo, list: l}
+}
+
+func (m Model) Init() tea.Cmd {
+	return m.load
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.list.SetSize(msg.Width, msg.Height-4)
+		return m, nil
+
+	case submodulesLoadedMsg:
+		if msg.err != nil {
+			m.status = styles.ErrorLineStyle.Render("Error: ") + styles.SubtitleStyle.Render(msg.err.Error())
+			return m, nil
+		}
+		items := make([]list.Item, len(msg.subs))
+		for i, s := range msg.subs {
+			items[i] = submoduleItem{sub: s}
+		}
+		m.list.SetItems(items)
+		return m, nil
+
+	case updateDoneMsg:
+		m.updating = false
+		target := msg.path
+		if target == "" {
+			target = "all submodules"
+		}
+		if msg.err != nil {
+			m.status = styles.ErrorLineStyle.Render("Update failed: ") + styles.SubtitleStyle.Render(msg.err.Error())
+		} else {
+			m.status = styles.BadgeSuccess.Render("Updated ") + styles.HighlightStyle.Render(target)
+		}
+		return m, m.load
+
+	case tea.KeyMsg:
+		if m.updating {
+			return m, nil
+		}
+		switch msg.String() {
+		case "esc":
+			return m, func() tea.Msg { return CloseMsg{} }
+
+		case "enter":
+			item, ok := m.list.SelectedItem().(submoduleItem)
+			if !ok {
+				return m, nil
+			}
+			if item.sub.State == git.SubmoduleUninitialized {
+				m.status = styles.ErrorLineStyle.Render("Not initialized: ") + styles.SubtitleStyle.Render("press s to init and update it first")
+				return m, nil
+			}
+			return m, m.open(item.sub.Path)
+
+		case "s":
+			item, ok := m.list.SelectedItem().(submoduleItem)
+			if !ok {
+				return m, nil
+			}
+			return m, m.update(item.sub.Path)
+
+		case "S":
+			return m, m.update("")
+
+		case "r":
+			m.status = ""
+			return m, m.load
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m Model) View() string {
+	content := m.list.View()
+	if m.status != "" {
+		content += "\n" + lipgloss.NewStyle().MarginLeft(2).Render(m.status)
+	}
+	return content
+}
+
+func (m Model) load() tea.Msg {
+	subs, err := m.repo.Submodules()
+	return submodulesLoadedMsg{subs: subs, err: err}
+}
+
+func (m *Model) update(path string) tea.Cmd {
+	m.updating = true
+	target := path
+	if target == "" {
+		target = "all submodules"
+	}
+	m.status = styles.BadgePending.Render("Updating " + target + "...")
+	return func() tea.Msg {
+		return updateDoneMsg{path: path, err: m.repo.UpdateSubmodule(path)}
+	}
+}
+
+// open reopens hit scoped to the submodule at path.
+func (m Model) open(path string) tea.Cmd {
+	dir := filepath.Join(m.repo.Root(), path)
+	return func() tea.Msg {
+		repo, err := git.Open(dir)
+		if err == nil {
+			err = os.Chdir(dir)
+		}
+		if err != nil {
+			return submodulesLoadedMsg{err: err}
+		}
+		return worktrees.SwitchMsg{Repo: repo}
+	}
+}
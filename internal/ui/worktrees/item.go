@@ -0,0 +1,44 @@
+package worktrees
+
+import (
+	"strings"
+
+	"github.com/elisa-content-delivery/hit/internal/git"
+	"github.com/elisa-content-delivery/hit/internal/styles"
+)
+
+type worktreeItem struct{ wt git.Worktree }
+
+func (w worktreeItem) Title() string {
+	prefix := "  "
+	if w.wt.Current {
+		prefix = styles.HighlightStyle.Render(styles.IconCheck + " ")
+	}
+	return prefix + w.wt.Path
+}
+
+func (w worktreeItem) Description() string {
+	var parts []string
+	switch {
+	case w.wt.Bare:
+		parts = append(parts, styles.SubtitleStyle.Render("bare"))
+	case w.wt.Detached:
+		parts = append(parts, styles.SubtitleStyle.Render("detached at "+w.wt.Head))
+	default:
+		parts = append(parts, styles.HighlightStyle.Render(styles.IconBranch+" "+w.wt.Branch)+" "+styles.SubtitleStyle.Render(w.wt.Head))
+	}
+	if w.wt.Dirty {
+		parts = append(parts, styles.BadgePending.Render(styles.IconEdit+" dirty"))
+	} else if !w.wt.Bare && !w.wt.Prunable {
+		parts = append(parts, styles.BadgeSuccess.Render(styles.IconCheck+" clean"))
+	}
+	if w.wt.Locked {
+		parts = append(parts, styles.SubtitleStyle.Render("locked"))
+	}
+	if w.wt.Prunable {
+		parts = append(parts, styles.ErrorLineStyle.Render("missing (prunable)"))
+	}
+	return strings.Join(parts, " · ")
+}
+
+func (w worktreeItem) FilterValue() string { return w.wt.Path + " " + w.wt.Branch }
@@ -0,0 +1,294 @@
+package worktrees
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/elisa-content-delivery/hit/internal/git"
+	"github.com/elisa-content-delivery/hit/internal/styles"
+)
+
+// SwitchMsg asks the app to reopen hit on another repository.
+type SwitchMsg struct {
+	Repo *git.Repo
+}
+
+type worktreesLoadedMsg struct {
+	trees []git.Worktree
+	err   error
+}
+
+type actionDoneMsg struct {
+	action string
+	path   string
+	err    error
+}
+
+type inputStep int
+
+const (
+	inputNone inputStep = iota
+	inputBranch
+	inputPR
+	inputPath
+)
+
+type Model struct {
+	repo          *git.Repo
+	list          list.Model
+	input         textinput.Model
+	step          inputStep
+	branch        string
+	pr            int
+	confirmRemove bool
+	width         int
+	height        int
+	status        string
+}
+
+func New(repo *git.Repo) Model {
+	l := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	l.Title = "Worktrees"
+	l.SetShowHelp(false)
+	l.SetFilteringEnabled(true)
+	l.SetStatusBarItemName("worktree", "worktrees")
+	l.Styles.Title = styles.TitleStyle
+
+	ti := textinput.New()
+	ti.CharLimit = 256
+
+	return Model{repo: repo, list: l, input: ti}
+}
+
+func (m Model) Init() tea.Cmd {
+	return m.loadWorktrees
+}
+
+func (m Model) IsInputActive() bool {
+	return m.step != inputNone || m.confirmRemove || m.list.FilterState() == list.Filtering
+}
+
+func (m Model) IsConfirming() bool {
+	return m.confirmRemove
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.list.SetSize(msg.Width, msg.Height-4)
+		return m, nil
+
+	case worktreesLoadedMsg:
+		if msg.err != nil {
+			m.status = styles.ErrorLineStyle.Render("Error: ") + styles.SubtitleStyle.Render(msg.err.Error())
+			return m, nil
+		}
+		items := make([]list.Item, len(msg.trees))
+		for i, wt := range msg.trees {
+			items[i] = worktreeItem{wt: wt}
+		}
+		m.list.SetItems(items)
+		return m, nil
+
+	case actionDoneMsg:
+		if msg.err != nil {
+			m.status = styles.ErrorLineStyle.Render(msg.action+" failed: ") + styles.SubtitleStyle.Render(msg.err.Error())
+			return m, m.loadWorktrees
+		}
+		m.status = styles.BadgeSuccess.Render(msg.action+" ") + styles.HighlightStyle.Render(msg.path)
+		return m, m.loadWorktrees
+
+	case tea.KeyMsg:
+		if m.confirmRemove {
+			return m.handleConfirmRemove(msg)
+		}
+		if m.step != inputNone {
+			return m.handleInput(msg)
+		}
+		if m.list.FilterState() == list.Filtering {
+			break
+		}
+
+		switch msg.String() {
+		case "enter":
+			wt, ok := m.selected()
+			if !ok || wt.Current || wt.Bare || wt.Prunable {
+				return m, nil
+			}
+			return m, openWorktree(wt.Path)
+
+		case "a":
+			m.prompt(inputBranch, "Branch: ", "")
+			return m, m.input.Cursor.BlinkCmd()
+
+		case "P":
+			m.prompt(inputPR, "Pull request #: ", "")
+			return m, m.input.Cursor.BlinkCmd()
+
+		case "d":
+			wt, ok := m.selected()
+			if !ok {
+				return m, nil
+			}
+			if wt.Current || wt == m.mainWorktree() {
+				m.status = styles.ErrorLineStyle.Render("Cannot remove ") + styles.SubtitleStyle.Render("the main or current worktree")
+				return m, nil
+			}
+			m.confirmRemove = true
+			return m, nil
+
+		case "p":
+			m.status = styles.BadgePending.Render("Pruning worktrees...")
+			return m, m.runAction("Pruned", "stale worktrees", m.repo.PruneWorktrees)
+
+		case "r":
+			m.status = ""
+			return m, m.loadWorktrees
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) prompt(step inputStep, prompt, value string) {
+	m.step = step
+	m.input.Prompt = prompt
+	m.input.SetValue(value)
+	m.input.CursorEnd()
+	m.input.Focus()
+	m.status = ""
+}
+
+func (m Model) handleInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.step = inputNone
+		return m, nil
+	case "enter":
+		value := strings.TrimSpace(m.input.Value())
+		if value == "" {
+			return m, nil
+		}
+		switch m.step {
+		case inputBranch:
+			m.branch, m.pr = value, 0
+			m.prompt(inputPath, "Path: ", m.repo.SiblingWorktreePath(value))
+			return m, nil
+		case inputPR:
+			n, err := strconv.Atoi(strings.TrimPrefix(value, "#"))
+			if err != nil || n <= 0 {
+				m.status = styles.ErrorLineStyle.Render("Invalid pull request number: ") + styles.SubtitleStyle.Render(value)
+				return m, nil
+			}
+			m.branch, m.pr = "", n
+			m.prompt(inputPath, "Path: ", m.repo.SiblingWorktreePath(fmt.Sprintf("pr-%d", n)))
+			return m, nil
+		}
+
+		m.step = inputNone
+		path := value
+		if m.pr > 0 {
+			n := m.pr
+			m.status = styles.BadgePending.Render(fmt.Sprintf("Fetching #%d into %s...", n, path))
+			return m, m.runAction("Created", path, func() error {
+				return m.repo.AddPRWorktree(path, n)
+			})
+		}
+		branch := m.branch
+		m.status = styles.BadgePending.Render("Creating " + path + "...")
+		return m, m.runAction("Created", path, func() error {
+			return m.repo.AddWorktree(path, branch)
+		})
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m Model) handleConfirmRemove(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "y":
+		m.confirmRemove = false
+		wt, ok := m.selected()
+		if !ok {
+			return m, nil
+		}
+		path, force := wt.Path, wt.Dirty
+		m.status = styles.BadgePending.Render("Removing " + path + "...")
+		return m, m.runAction("Removed", path, func() error {
+			return m.repo.RemoveWorktree(path, force)
+		})
+	case "n", "esc":
+		m.confirmRemove = false
+	}
+	return m, nil
+}
+
+func (m Model) View() string {
+	content := m.list.View()
+
+	if m.step != inputNone {
+		content += "\n" + lipgloss.NewStyle().MarginLeft(2).Render(m.input.View())
+	}
+	if m.confirmRemove {
+		wt, _ := m.selected()
+		warning := "(y/n)"
+		if wt.Dirty {
+			warning = "It has uncommitted changes that will be lost. (y/n)"
+		}
+		content += "\n" + lipgloss.NewStyle().MarginLeft(2).Render(
+			styles.BadgePending.Render("Remove worktree "+wt.Path+"? ")+styles.SubtitleStyle.Render(warning))
+	} else if m.status != "" {
+		content += "\n" + lipgloss.NewStyle().MarginLeft(2).Render(m.status)
+	}
+	return content
+}
+
+func (m Model) selected() (git.Worktree, bool) {
+	item, ok := m.list.SelectedItem().(worktreeItem)
+	return item.wt, ok
+}
+
+func (m Model) mainWorktree() git.Worktree {
+	if items := m.list.Items(); len(items) > 0 {
+		if item, ok := items[0].(worktreeItem); ok {
+			return item.wt
+		}
+	}
+	return git.Worktree{}
+}
+
+func (m Model) loadWorktrees() tea.Msg {
+	trees, err := m.repo.ListWorktrees()
+	return worktreesLoadedMsg{trees: trees, err: err}
+}
+
+func openWorktree(path string) tea.Cmd {
+	return func() tea.Msg {
+		repo, err := git.Open(path)
+		if err == nil {
+			err = os.Chdir(path)
+		}
+		if err != nil {
+			return actionDoneMsg{action: "Open", path: path, err: err}
+		}
+		return SwitchMsg{Repo: repo}
+	}
+}
+
+func (m Model) runAction(name, path string, fn func() error) tea.Cmd {
+	return func() tea.Msg {
+		return actionDoneMsg{action: name, path: path, err: fn()}
+	}
+}
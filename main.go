@@ -10,20 +10,21 @@ import (
 )
 
 func main() {
+	// Outside a repository hit starts on the Org view, where a repo can be
+	// cloned and opened
+	var host, owner, repoName string
 	repo, err := git.OpenCwd()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
-		os.Exit(1)
-	}
-
-	owner, repoName, err := repo.OwnerRepo()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: %s (CI features disabled)\n", err)
-		owner = ""
-		repoName = ""
+	if err == nil {
+		host, owner, repoName, err = repo.GitHubRepo()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s (CI features disabled)\n", err)
+			host = ""
+			owner = ""
+			repoName = ""
+		}
 	}
 
-	model := app.NewModel(repo, owner, repoName)
+	model := app.NewModel(repo, host, owner, repoName)
 	p := tea.NewProgram(model, tea.WithAltScreen())
 
 	if _, err := p.Run(); err != nil {